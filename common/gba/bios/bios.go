@@ -0,0 +1,39 @@
+package bios
+
+import "device/arm"
+
+// BIOSの関数（ソフトウェア割り込み）
+// ARMモードのswiは番号を上位8bitに置く（0x03なら0x030000）
+// BIOSはr0-r3とr12を書き換えるが、AsmFullでは書き換えるレジスタを指定できないため、
+// swiの前後で退避してコンパイラが使っている値を壊さないようにする
+
+// Halt 割り込みが起きるまでCPUを止める（IEで有効な割り込みで再開する）
+func Halt() {
+	arm.AsmFull(`
+		push {r0-r3, r12}
+		swi 0x020000
+		pop {r0-r3, r12}
+	`, map[string]interface{}{})
+}
+
+// Stop 低消費電力の停止状態にする
+// キー入力・カートリッジ・通信の割り込みでしか再開しないため、先にIEとKEYCNTを設定しておく
+// 画面（強制ブランク）と音は呼ぶ前に止める
+func Stop() {
+	arm.AsmFull(`
+		push {r0-r3, r12}
+		swi 0x030000
+		pop {r0-r3, r12}
+	`, map[string]interface{}{})
+}
+
+// VBlankIntrWait 次のVBlank割り込みまでCPUを止める
+// BIOSは割り込みの処理が立てたBIOSの割り込みフラグで再開するため、
+// interrupt.SetでVBlankの処理を設定しておく（フラグはinterruptパッケージが立てる）
+func VBlankIntrWait() {
+	arm.AsmFull(`
+		push {r0-r3, r12}
+		swi 0x050000
+		pop {r0-r3, r12}
+	`, map[string]interface{}{})
+}
@@ -0,0 +1,62 @@
+package display
+
+import (
+	"runtime/volatile"
+	"unsafe"
+)
+
+// 背景のスクロール・アフィン変換のレジスタアドレス
+const (
+	RegBG0HOFS = 0x04000010 // BG0の水平スクロール（BGnは4バイトずつずれる）
+	RegBG0VOFS = 0x04000012 // BG0の垂直スクロール
+
+	RegBG2PA = 0x04000020 // BG2のアフィン行列（8.8固定小数点）
+	RegBG2PB = 0x04000022
+	RegBG2PC = 0x04000024
+	RegBG2PD = 0x04000026
+	RegBG2X  = 0x04000028 // BG2の基準点X（20.8固定小数点）
+	RegBG2Y  = 0x0400002C // BG2の基準点Y
+)
+
+var (
+	BG2PA = (*volatile.Register16)(unsafe.Pointer(uintptr(RegBG2PA)))
+	BG2PB = (*volatile.Register16)(unsafe.Pointer(uintptr(RegBG2PB)))
+	BG2PC = (*volatile.Register16)(unsafe.Pointer(uintptr(RegBG2PC)))
+	BG2PD = (*volatile.Register16)(unsafe.Pointer(uintptr(RegBG2PD)))
+	BG2X  = (*volatile.Register32)(unsafe.Pointer(uintptr(RegBG2X)))
+	BG2Y  = (*volatile.Register32)(unsafe.Pointer(uintptr(RegBG2Y)))
+)
+
+// SetBGScroll タイルモードの背景（0-3）のスクロール位置を設定
+// レジスタは書き込み専用なので、現在の値は呼び出し側で覚えておく
+func SetBGScroll(bg int, x, y int) {
+	if bg < 0 || bg > 3 {
+		return
+	}
+	base := uintptr(RegBG0HOFS + bg*4)
+	(*volatile.Register16)(unsafe.Pointer(base)).Set(uint16(x) & 0x1FF)
+	(*volatile.Register16)(unsafe.Pointer(base + 2)).Set(uint16(y) & 0x1FF)
+}
+
+// SetBG2Affine BG2のアフィン変換を設定（Mode 1-5）
+// pa, pb, pc, pd: 画面1ピクセルあたりの背景の移動量（8.8固定小数点、0x100で等倍）
+// x, y: 画面左上に対応する背景の座標（ピクセル単位）
+func SetBG2Affine(pa, pb, pc, pd int16, x, y int) {
+	BG2PA.Set(uint16(pa))
+	BG2PB.Set(uint16(pb))
+	BG2PC.Set(uint16(pc))
+	BG2PD.Set(uint16(pd))
+	BG2X.Set(uint32(int32(x) << 8))
+	BG2Y.Set(uint32(int32(y) << 8))
+}
+
+// SetBG2Offset 等倍のまま、画面左上に表示するBG2の位置だけを設定
+// ビットマップモードでは画面全体を平行移動でき、はみ出た部分は背景色になる
+func SetBG2Offset(x, y int) {
+	SetBG2Affine(0x100, 0, 0, 0x100, x, y)
+}
+
+// ResetBG2Affine BG2のアフィン変換を初期状態（等倍、ずらしなし）に戻す
+func ResetBG2Affine() {
+	SetBG2Offset(0, 0)
+}
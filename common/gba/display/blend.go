@@ -0,0 +1,73 @@
+package display
+
+import (
+	"runtime/volatile"
+	"unsafe"
+)
+
+// カラー特殊効果のレジスタアドレス
+const (
+	RegBLDCNT   = 0x04000050 // 効果の種類と対象レイヤー
+	RegBLDALPHA = 0x04000052 // 半透明の係数
+	RegBLDY     = 0x04000054 // 明るさの係数
+)
+
+// 効果の対象レイヤー（第1対象は下位、第2対象は8bit上にずらして使う）
+const (
+	BlendBG0      = 1 << 0
+	BlendBG1      = 1 << 1
+	BlendBG2      = 1 << 2
+	BlendBG3      = 1 << 3
+	BlendOBJ      = 1 << 4
+	BlendBackdrop = 1 << 5
+	BlendAll      = BlendBG0 | BlendBG1 | BlendBG2 | BlendBG3 | BlendOBJ | BlendBackdrop
+)
+
+// 効果の種類
+const (
+	BlendOff      = 0 << 6 // 効果なし
+	BlendAlpha    = 1 << 6 // 半透明（第1対象と第2対象を合成）
+	BlendBrighten = 2 << 6 // 第1対象を白に近づける
+	BlendDarken   = 3 << 6 // 第1対象を黒に近づける
+)
+
+// BlendMax 係数の最大値（16で100%）
+const BlendMax = 16
+
+var (
+	BLDCNT   = (*volatile.Register16)(unsafe.Pointer(uintptr(RegBLDCNT)))
+	BLDALPHA = (*volatile.Register16)(unsafe.Pointer(uintptr(RegBLDALPHA)))
+	BLDY     = (*volatile.Register16)(unsafe.Pointer(uintptr(RegBLDY)))
+)
+
+// SetBlend 効果の種類と対象レイヤーを設定
+// mode: BlendAlphaなど、target1/target2: BlendBG0などの組み合わせ
+func SetBlend(mode uint16, target1, target2 uint8) {
+	BLDCNT.Set(mode | uint16(target1) | uint16(target2)<<8)
+}
+
+// SetBlendAlpha 半透明の係数を設定（0-16、結果 = 第1対象×eva/16 + 第2対象×evb/16）
+func SetBlendAlpha(eva, evb int) {
+	BLDALPHA.Set(uint16(clampBlend(eva)) | uint16(clampBlend(evb))<<8)
+}
+
+// SetBlendBrightness 明るさの係数を設定（0-16、16で完全に白または黒）
+func SetBlendBrightness(evy int) {
+	BLDY.Set(uint16(clampBlend(evy)))
+}
+
+// DisableBlend カラー特殊効果を止める
+func DisableBlend() {
+	BLDCNT.Set(BlendOff)
+}
+
+// clampBlend 係数を0-16に収める
+func clampBlend(v int) int {
+	if v < 0 {
+		return 0
+	}
+	if v > BlendMax {
+		return BlendMax
+	}
+	return v
+}
@@ -0,0 +1,73 @@
+package display
+
+import (
+	"runtime/volatile"
+	"unsafe"
+)
+
+// ウィンドウのレジスタアドレス
+const (
+	RegWIN0H  = 0x04000040 // Window 0 の左右端
+	RegWIN1H  = 0x04000042 // Window 1 の左右端
+	RegWIN0V  = 0x04000044 // Window 0 の上下端
+	RegWIN1V  = 0x04000046 // Window 1 の上下端
+	RegWININ  = 0x04000048 // Window 0/1 の内側に表示するレイヤー
+	RegWINOUT = 0x0400004A // ウィンドウの外側とOBJ Windowに表示するレイヤー
+)
+
+// ウィンドウに表示するレイヤー（WININ/WINOUTの各バイト）
+const (
+	WinBG0   = 1 << 0
+	WinBG1   = 1 << 1
+	WinBG2   = 1 << 2
+	WinBG3   = 1 << 3
+	WinOBJ   = 1 << 4
+	WinBlend = 1 << 5 // カラー特殊効果（半透明・明るさ）を有効にする
+	WinAll   = WinBG0 | WinBG1 | WinBG2 | WinBG3 | WinOBJ | WinBlend
+)
+
+var (
+	WIN0H  = (*volatile.Register16)(unsafe.Pointer(uintptr(RegWIN0H)))
+	WIN1H  = (*volatile.Register16)(unsafe.Pointer(uintptr(RegWIN1H)))
+	WIN0V  = (*volatile.Register16)(unsafe.Pointer(uintptr(RegWIN0V)))
+	WIN1V  = (*volatile.Register16)(unsafe.Pointer(uintptr(RegWIN1V)))
+	WININ  = (*volatile.Register16)(unsafe.Pointer(uintptr(RegWININ)))
+	WINOUT = (*volatile.Register16)(unsafe.Pointer(uintptr(RegWINOUT)))
+)
+
+// WindowSpan ウィンドウの左右端（または上下端）のレジスタ値
+// start以上end未満の範囲。start == end なら空
+func WindowSpan(start, end int) uint16 {
+	if start < 0 {
+		start = 0
+	}
+	if end > 255 {
+		end = 255
+	}
+	if end <= start {
+		return 0
+	}
+	return uint16(start)<<8 | uint16(end)
+}
+
+// SetWindow0 Window 0 の矩形を設定（x1, y1は含まない）
+func SetWindow0(x0, y0, x1, y1 int) {
+	WIN0H.Set(WindowSpan(x0, x1))
+	WIN0V.Set(WindowSpan(y0, y1))
+}
+
+// SetWindow1 Window 1 の矩形を設定
+func SetWindow1(x0, y0, x1, y1 int) {
+	WIN1H.Set(WindowSpan(x0, x1))
+	WIN1V.Set(WindowSpan(y0, y1))
+}
+
+// SetWindowIn Window 0/1 の内側に表示するレイヤーを設定（WinBG0などの組み合わせ）
+func SetWindowIn(win0, win1 uint8) {
+	WININ.Set(uint16(win0) | uint16(win1)<<8)
+}
+
+// SetWindowOut ウィンドウの外側とOBJ Windowの内側に表示するレイヤーを設定
+func SetWindowOut(outside, objWindow uint8) {
+	WINOUT.Set(uint16(outside) | uint16(objWindow)<<8)
+}
@@ -0,0 +1,145 @@
+package graphics
+
+import "github.com/ryomak/gameboys/common/math"
+
+// 角度は math.Sin/Cos と同じ256段階（0-255 が 0-360度）
+// 0が右、反時計回り（画面上では上向き）に増える
+
+// DrawArc 円弧を描画
+// start から end まで反時計回りに描く。end-start が256以上なら円全体
+func DrawArc(cx, cy, radius int, start, end int32, color uint16) {
+	mode3Canvas(color).arc(cx, cy, radius, start, end, false)
+}
+
+// FillPie 扇形を塗りつぶす
+func FillPie(cx, cy, radius int, start, end int32, color uint16) {
+	mode3Canvas(color).arc(cx, cy, radius, start, end, true)
+}
+
+// DrawArcMode4 Mode 4で円弧を描画
+func DrawArcMode4(cx, cy, radius int, start, end int32, colorIndex uint8) {
+	mode4Canvas(colorIndex).arc(cx, cy, radius, start, end, false)
+}
+
+// FillPieMode4 Mode 4で扇形を塗りつぶす
+func FillPieMode4(cx, cy, radius int, start, end int32, colorIndex uint8) {
+	mode4Canvas(colorIndex).arc(cx, cy, radius, start, end, true)
+}
+
+// sector 扇形の角度範囲の判定
+type sector struct {
+	full   bool
+	wide   bool       // 180度を超えるか
+	sx, sy math.Fixed // 開始方向
+	ex, ey math.Fixed // 終了方向
+}
+
+// newSector 角度範囲を作成（範囲が空ならok=false）
+func newSector(start, end int32) (s sector, ok bool) {
+	sweep := end - start
+	if sweep <= 0 {
+		return s, false
+	}
+	if sweep >= math.AngleMax {
+		s.full = true
+		return s, true
+	}
+	s.wide = sweep > math.AngleHalf
+	s.sx, s.sy = math.Cos(start), math.Sin(start)
+	s.ex, s.ey = math.Cos(end), math.Sin(end)
+	return s, true
+}
+
+// contains 中心からのオフセット(dx, dy)（画面座標、yは下向き）が範囲内か
+// 開始・終了方向との外積の符号で判定する
+func (s sector) contains(dx, dy int) bool {
+	if s.full {
+		return true
+	}
+	px := math.Fixed(dx)
+	py := math.Fixed(-dy)
+	afterStart := s.sx*py-s.sy*px >= 0 // 開始方向から反時計回り側
+	beforeEnd := px*s.ey-py*s.ex >= 0  // 終了方向から時計回り側
+	if s.wide {
+		return afterStart || beforeEnd
+	}
+	return afterStart && beforeEnd
+}
+
+// arc 円弧または扇形を描画
+func (c canvas) arc(cx, cy, radius int, start, end int32, fill bool) {
+	if radius <= 0 {
+		return
+	}
+	s, ok := newSector(start, end)
+	if !ok {
+		return
+	}
+	if fill {
+		c.pie(cx, cy, radius, s)
+		return
+	}
+
+	// DrawCircleと同じ点列のうち、範囲内の点だけを描く
+	x := radius
+	y := 0
+	err := 0
+	for x >= y {
+		c.sectorPixel(cx, cy, x, y, s)
+		c.sectorPixel(cx, cy, y, x, s)
+		c.sectorPixel(cx, cy, -y, x, s)
+		c.sectorPixel(cx, cy, -x, y, s)
+		c.sectorPixel(cx, cy, -x, -y, s)
+		c.sectorPixel(cx, cy, -y, -x, s)
+		c.sectorPixel(cx, cy, y, -x, s)
+		c.sectorPixel(cx, cy, x, -y, s)
+
+		if err <= 0 {
+			y++
+			err += 2*y + 1
+		}
+		if err > 0 {
+			x--
+			err -= 2*x + 1
+		}
+	}
+}
+
+// sectorPixel 範囲内ならピクセルを描画
+func (c canvas) sectorPixel(cx, cy, dx, dy int, s sector) {
+	if s.contains(dx, dy) {
+		c.pixel(cx+dx, cy+dy)
+	}
+}
+
+// pie 扇形を塗りつぶす（行ごとに範囲内の連続区間を水平線で描く）
+func (c canvas) pie(cx, cy, radius int, s sector) {
+	r2 := radius*radius + radius // 円の縁を滑らかにするため半径を少し広げる
+	_, top, _, bottom := c.bounds()
+	yStart := max(-radius, top-cy)
+	yEnd := min(radius, bottom-1-cy)
+	for dy := yStart; dy <= yEnd; dy++ {
+		half := int(math.IntSqrt(int32(r2 - dy*dy)))
+		for half*half > r2-dy*dy {
+			half--
+		}
+		for (half+1)*(half+1) <= r2-dy*dy {
+			half++
+		}
+		runStart := 0
+		inRun := false
+		for dx := -half; dx <= half; dx++ {
+			in := s.contains(dx, dy)
+			if in && !inRun {
+				runStart = dx
+				inRun = true
+			} else if !in && inRun {
+				c.hline(cx+runStart, cx+dx-1, cy+dy)
+				inRun = false
+			}
+		}
+		if inRun {
+			c.hline(cx+runStart, cx+half, cy+dy)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package graphics
+
+import (
+	"runtime/volatile"
+	"unsafe"
+
+	"github.com/ryomak/gameboys/common/math"
+)
+
+// Image16 Mode 3用の画像（16bitカラー）
+type Image16 struct {
+	Width, Height int
+	Pixels        []uint16 // Width*Height個のピクセル（行優先）
+	Transparent   bool     // trueならKeyと同じ色のピクセルを描画しない
+	Key           uint16   // 透過色
+}
+
+// Image8 Mode 4用の画像（8bitパレットインデックス）
+type Image8 struct {
+	Width, Height int
+	Pixels        []uint8 // Width*Height個のピクセル（行優先）
+	Transparent   bool    // trueならKeyと同じインデックスのピクセルを描画しない
+	Key           uint8   // 透過インデックス
+}
+
+// rotoSetup 回転拡大縮小描画の準備
+// 描画先の矩形（画面座標、クリップ済み）と、その左上ピクセルに対応する画像座標、
+// 描画先1ピクセルごとの画像座標の増分を返す
+func rotoSetup(cx, cy, width, height int, angle int32, scale math.Fixed, screenW, screenH int) (
+	x0, y0, x1, y1 int, u, v, dudx, dvdx, dudy, dvdy math.Fixed, ok bool) {
+	if width <= 0 || height <= 0 || scale <= 0 {
+		return
+	}
+	cx += clip.ox
+	cy += clip.oy
+	clipX0, clipY0, clipX1, clipY1 := clipArea(screenW, screenH)
+
+	cos := math.Cos(angle)
+	sin := math.Sin(angle)
+
+	// 回転後の外接矩形の半分のサイズ
+	extW := cos.Abs().Mul(math.NewFixed(int32(width))).Add(sin.Abs().Mul(math.NewFixed(int32(height))))
+	extH := sin.Abs().Mul(math.NewFixed(int32(width))).Add(cos.Abs().Mul(math.NewFixed(int32(height))))
+	halfW := int(extW.Mul(scale).Ceil()/2) + 1
+	halfH := int(extH.Mul(scale).Ceil()/2) + 1
+
+	x0 = max(cx-halfW, clipX0)
+	y0 = max(cy-halfH, clipY0)
+	x1 = min(cx+halfW, clipX1)
+	y1 = min(cy+halfH, clipY1)
+	if x0 >= x1 || y0 >= y1 {
+		return
+	}
+
+	// 逆写像：描画先のオフセットを-angle回転して1/scale倍すると画像座標になる
+	invScale := math.FixedOne.Div(scale)
+	dudx = cos.Mul(invScale)
+	dvdx = sin.Mul(invScale)
+	dudy = -sin.Mul(invScale)
+	dvdy = cos.Mul(invScale)
+
+	// 左上ピクセルの中心に対応する画像座標
+	dx := math.NewFixed(int32(x0 - cx)).Add(math.FixedHalf)
+	dy := math.NewFixed(int32(y0 - cy)).Add(math.FixedHalf)
+	u = dx.Mul(dudx).Add(dy.Mul(dudy)).Add(math.NewFixed(int32(width)) >> 1)
+	v = dx.Mul(dvdx).Add(dy.Mul(dvdy)).Add(math.NewFixed(int32(height)) >> 1)
+
+	ok = true
+	return
+}
+
+// RotoBlit 画像を回転・拡大縮小して描画（Mode 3用）
+// (cx, cy): 描画先での画像中心
+// angle: 回転角（0-255 が 0-360度、反時計回り）
+// scale: 拡大率（FixedOneで等倍）
+func RotoBlit(cx, cy int, img *Image16, angle int32, scale math.Fixed) {
+	x0, y0, x1, y1, rowU, rowV, dudx, dvdx, dudy, dvdy, ok :=
+		rotoSetup(cx, cy, img.Width, img.Height, angle, scale, ScreenWidth, ScreenHeight)
+	if !ok {
+		return
+	}
+
+	for y := y0; y < y1; y++ {
+		u, v := rowU, rowV
+		offset := y * ScreenWidth
+		for x := x0; x < x1; x++ {
+			iu := int(u.Floor())
+			iv := int(v.Floor())
+			if iu >= 0 && iu < img.Width && iv >= 0 && iv < img.Height {
+				color := img.Pixels[iv*img.Width+iu]
+				if !img.Transparent || color != img.Key {
+					VideoBuffer[offset+x] = color
+				}
+			}
+			u += dudx
+			v += dvdx
+		}
+		rowU += dudy
+		rowV += dvdy
+	}
+}
+
+// RotoBlitMode4 画像を回転・拡大縮小して描画（Mode 4用、バックバッファに描画）
+func RotoBlitMode4(cx, cy int, img *Image8, angle int32, scale math.Fixed) {
+	x0, y0, x1, y1, rowU, rowV, dudx, dvdx, dudy, dvdy, ok :=
+		rotoSetup(cx, cy, img.Width, img.Height, angle, scale, Mode4Width, Mode4Height)
+	if !ok {
+		return
+	}
+
+	addr := GetMode4BackBuffer()
+
+	for y := y0; y < y1; y++ {
+		u, v := rowU, rowV
+		offset := uintptr(y * Mode4Width)
+		for x := x0; x < x1; x++ {
+			iu := int(u.Floor())
+			iv := int(v.Floor())
+			if iu >= 0 && iu < img.Width && iv >= 0 && iv < img.Height {
+				colorIndex := img.Pixels[iv*img.Width+iu]
+				if !img.Transparent || colorIndex != img.Key {
+					ptr := (*volatile.Register8)(unsafe.Pointer(addr + offset + uintptr(x)))
+					ptr.Set(colorIndex)
+				}
+			}
+			u += dudx
+			v += dvdx
+		}
+		rowU += dudy
+		rowV += dvdy
+	}
+}
+
+// RotoBlitProjected 射影結果の位置とスケールで画像を描画（Mode 3用）
+// 3D空間上のビルボード描画に使う。baseScale: 射影スケール1.0のときの拡大率
+func RotoBlitProjected(result math.ProjectionResult, img *Image16, angle int32, baseScale math.Fixed) {
+	if !result.Visible {
+		return
+	}
+	RotoBlit(int(result.ScreenX), int(result.ScreenY), img, angle, result.Scale.Mul(baseScale))
+}
+
+// RotoBlitProjectedMode4 射影結果の位置とスケールで画像を描画（Mode 4用）
+func RotoBlitProjectedMode4(result math.ProjectionResult, img *Image8, angle int32, baseScale math.Fixed) {
+	if !result.Visible {
+		return
+	}
+	RotoBlitMode4(int(result.ScreenX), int(result.ScreenY), img, angle, result.Scale.Mul(baseScale))
+}
+
+// clampSource 画像の一部(sx, sy, w, h)を画像の中に収める
+// 左・上にはみ出した分は描画先(x, y)もずらし、画像の同じ位置が同じ場所に描かれるようにする
+func clampSource(x, y, sx, sy, w, h, imgW, imgH int) (int, int, int, int, int, int) {
+	if sx < 0 {
+		x -= sx
+		w += sx
+		sx = 0
+	}
+	if sy < 0 {
+		y -= sy
+		h += sy
+		sy = 0
+	}
+	w = min(w, imgW-sx)
+	h = min(h, imgH-sy)
+	return x, y, sx, sy, w, h
+}
+
+// clipRegion 画像の一部(sx, sy, w, h)を(x, y)に描画するときの範囲をクリップ
+// 描画先は画面座標に変換して返す
+func clipRegion(x, y, sx, sy, w, h, screenW, screenH int) (int, int, int, int, int, int, bool) {
+	x += clip.ox
+	y += clip.oy
+	x0, y0, x1, y1 := clipArea(screenW, screenH)
+	if x < x0 {
+		sx += x0 - x
+		w -= x0 - x
+		x = x0
+	}
+	if y < y0 {
+		sy += y0 - y
+		h -= y0 - y
+		y = y0
+	}
+	w = min(w, x1-x)
+	h = min(h, y1-y)
+	return x, y, sx, sy, w, h, w > 0 && h > 0
+}
+
+// Blit 画像をそのまま描画（Mode 3用）
+func Blit(x, y int, img *Image16) {
+	BlitRegion(x, y, img, 0, 0, img.Width, img.Height)
+}
+
+// BlitRegion 画像の一部(sx, sy, w, h)を(x, y)に描画（Mode 3用）
+func BlitRegion(x, y int, img *Image16, sx, sy, w, h int) {
+	x, y, sx, sy, w, h = clampSource(x, y, sx, sy, w, h, img.Width, img.Height)
+	x, y, sx, sy, w, h, ok := clipRegion(x, y, sx, sy, w, h, ScreenWidth, ScreenHeight)
+	if !ok {
+		return
+	}
+
+	for row := 0; row < h; row++ {
+		src := (sy+row)*img.Width + sx
+		dst := (y+row)*ScreenWidth + x
+		for col := 0; col < w; col++ {
+			color := img.Pixels[src+col]
+			if !img.Transparent || color != img.Key {
+				VideoBuffer[dst+col] = color
+			}
+		}
+	}
+}
+
+// BlitMode4 画像をそのまま描画（Mode 4用、バックバッファに描画）
+func BlitMode4(x, y int, img *Image8) {
+	BlitRegionMode4(x, y, img, 0, 0, img.Width, img.Height)
+}
+
+// BlitRegionMode4 画像の一部(sx, sy, w, h)を(x, y)に描画（Mode 4用）
+func BlitRegionMode4(x, y int, img *Image8, sx, sy, w, h int) {
+	x, y, sx, sy, w, h = clampSource(x, y, sx, sy, w, h, img.Width, img.Height)
+	x, y, sx, sy, w, h, ok := clipRegion(x, y, sx, sy, w, h, Mode4Width, Mode4Height)
+	if !ok {
+		return
+	}
+
+	addr := GetMode4BackBuffer()
+
+	for row := 0; row < h; row++ {
+		src := (sy+row)*img.Width + sx
+		offset := uintptr((y+row)*Mode4Width + x)
+		for col := 0; col < w; col++ {
+			colorIndex := img.Pixels[src+col]
+			if !img.Transparent || colorIndex != img.Key {
+				ptr := (*volatile.Register8)(unsafe.Pointer(addr + offset + uintptr(col)))
+				ptr.Set(colorIndex)
+			}
+		}
+	}
+}
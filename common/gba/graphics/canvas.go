@@ -0,0 +1,329 @@
+package graphics
+
+import (
+	"runtime/volatile"
+	"unsafe"
+)
+
+// canvasMode 描画先のモード
+type canvasMode uint8
+
+const (
+	canvasMode3 canvasMode = iota
+	canvasMode4
+	canvasMode5
+)
+
+// canvas Mode 3/4/5で共通の描画先
+// 図形アルゴリズムを1つの実装ですべてのモードに対応させるために使う
+// 座標はローカル座標（原点・クリップは各モードの描画関数が適用する）
+type canvas struct {
+	mode  canvasMode
+	color uint16 // Mode 3/5では15bitカラー、Mode 4ではパレットインデックス
+}
+
+// mode3Canvas Mode 3（VideoBuffer）への描画先
+func mode3Canvas(color uint16) canvas {
+	return canvas{mode: canvasMode3, color: color}
+}
+
+// mode4Canvas Mode 4（バックバッファ）への描画先
+func mode4Canvas(colorIndex uint8) canvas {
+	return canvas{mode: canvasMode4, color: uint16(colorIndex)}
+}
+
+// mode5Canvas Mode 5（バックバッファ）への描画先
+func mode5Canvas(color uint16) canvas {
+	return canvas{mode: canvasMode5, color: color}
+}
+
+// pixel 1ピクセル描画（クリップ外は無視）
+func (c canvas) pixel(x, y int) {
+	switch c.mode {
+	case canvasMode4:
+		SetMode4Pixel(x, y, uint8(c.color))
+	case canvasMode5:
+		SetMode5Pixel(x, y, c.color)
+	default:
+		DrawPixel(x, y, c.color)
+	}
+}
+
+// get ピクセルの値を取得（Mode 4ではパレットインデックス）
+func (c canvas) get(x, y int) uint16 {
+	switch c.mode {
+	case canvasMode4:
+		return uint16(GetMode4Pixel(x, y))
+	case canvasMode5:
+		return GetMode5Pixel(x, y)
+	default:
+		return GetPixel(x, y)
+	}
+}
+
+// hline x0からx1まで（両端を含む）の水平線を描画
+func (c canvas) hline(x0, x1, y int) {
+	if x0 > x1 {
+		x0, x1 = x1, x0
+	}
+	switch c.mode {
+	case canvasMode4:
+		FillRectMode4(x0, y, x1-x0+1, 1, uint8(c.color))
+	case canvasMode5:
+		FillRectMode5(x0, y, x1-x0+1, 1, c.color)
+	default:
+		DrawHLine(x0, y, x1-x0+1, c.color)
+	}
+}
+
+// vline y0からy1まで（両端を含む）の垂直線を描画
+func (c canvas) vline(x, y0, y1 int) {
+	if y0 > y1 {
+		y0, y1 = y1, y0
+	}
+	switch c.mode {
+	case canvasMode4:
+		FillRectMode4(x, y0, 1, y1-y0+1, uint8(c.color))
+	case canvasMode5:
+		FillRectMode5(x, y0, 1, y1-y0+1, c.color)
+	default:
+		DrawVLine(x, y0, y1-y0+1, c.color)
+	}
+}
+
+// put 画面座標にピクセルを書き込む（範囲チェックなし）
+func (c canvas) put(x, y int) {
+	switch c.mode {
+	case canvasMode4:
+		ptr := (*volatile.Register8)(unsafe.Pointer(GetMode4BackBuffer() + uintptr(y*Mode4Width+x)))
+		ptr.Set(uint8(c.color))
+	case canvasMode5:
+		mode5Ptr(x, y).Set(c.color)
+	default:
+		VideoBuffer[y*ScreenWidth+x] = c.color
+	}
+}
+
+// screenArea 描画できる範囲（画面座標、x1・y1は含まない）
+func (c canvas) screenArea() (x0, y0, x1, y1 int) {
+	switch c.mode {
+	case canvasMode4:
+		return clipArea(Mode4Width, Mode4Height)
+	case canvasMode5:
+		return clipArea(Mode5Width, Mode5Height)
+	default:
+		return clipArea(ScreenWidth, ScreenHeight)
+	}
+}
+
+// Cohen-Sutherlandの領域コード
+const (
+	outLeft = 1 << iota
+	outRight
+	outTop
+	outBottom
+)
+
+// outCode 点がクリップ矩形のどちら側にあるか
+func outCode(x, y, x0, y0, x1, y1 int) int {
+	code := 0
+	if x < x0 {
+		code |= outLeft
+	} else if x >= x1 {
+		code |= outRight
+	}
+	if y < y0 {
+		code |= outTop
+	} else if y >= y1 {
+		code |= outBottom
+	}
+	return code
+}
+
+// line 直線を描画（ブレゼンハムのアルゴリズム）
+// クリップ矩形の外の部分は1ピクセルずつ辿らずに飛ばす
+// 描かれるピクセルは、線全体を辿って範囲外を捨てた場合と同じ
+func (c canvas) line(x0, y0, x1, y1 int) {
+	// 水平線・垂直線は専用の処理
+	if y0 == y1 {
+		c.hline(x0, x1, y0)
+		return
+	}
+	if x0 == x1 {
+		c.vline(x0, y0, y1)
+		return
+	}
+
+	// 画面座標に変換
+	x0 += clip.ox
+	y0 += clip.oy
+	x1 += clip.ox
+	y1 += clip.oy
+	left, top, right, bottom := c.screenArea()
+	if left >= right || top >= bottom {
+		return
+	}
+
+	// 両端が同じ側の外にあれば何も描かない
+	if outCode(x0, y0, left, top, right, bottom)&outCode(x1, y1, left, top, right, bottom) != 0 {
+		return
+	}
+
+	dx := abs(x1 - x0)
+	dy := abs(y1 - y0)
+	sx := -1
+	if x0 < x1 {
+		sx = 1
+	}
+	sy := -1
+	if y0 < y1 {
+		sy = 1
+	}
+
+	// 主軸（毎ステップ進む軸）と副軸に分け、クリップ内に入るステップの範囲を求める
+	var first, last int
+	var ok bool
+	if dx >= dy {
+		first, last, ok = lineSteps(x0, sx, dx, left, right-1, y0, sy, dy, top, bottom-1)
+	} else {
+		first, last, ok = lineSteps(y0, sy, dy, top, bottom-1, x0, sx, dx, left, right-1)
+	}
+	if !ok {
+		return
+	}
+
+	// firstステップ目の位置と誤差項
+	x, y := x0, y0
+	var err int
+	if dx >= dy {
+		m := lineMinorOffset(first, dx, dy)
+		x += sx * first
+		y += sy * m
+		err = dx - dy - first*dy + m*dx
+	} else {
+		m := lineMinorOffset(first, dy, dx)
+		x += sx * m
+		y += sy * first
+		err = dx - dy + first*dx - m*dy
+	}
+
+	for k := first; k <= last; k++ {
+		c.put(x, y)
+		e2 := 2 * err
+		if e2 > -dy {
+			err -= dy
+			x += sx
+		}
+		if e2 < dx {
+			err += dx
+			y += sy
+		}
+	}
+}
+
+// lineMinorOffset ブレゼンハムでkステップ進んだときの副軸の移動量
+func lineMinorOffset(k, major, minor int) int {
+	return int((2*int64(k)*int64(minor) + int64(major) - 1) / (2 * int64(major)))
+}
+
+// lineSteps 主軸・副軸それぞれの範囲[lo, hi]に収まるステップの範囲を求める
+// p0, q0: 主軸・副軸の始点、sp, sq: 進む向き、major, minor: 各軸の移動量
+func lineSteps(p0, sp, major, pLo, pHi, q0, sq, minor, qLo, qHi int) (first, last int, ok bool) {
+	// 主軸: p0 + sp*k が範囲内
+	first, last = 0, major
+	if sp > 0 {
+		first = max(first, pLo-p0)
+		last = min(last, pHi-p0)
+	} else {
+		first = max(first, p0-pHi)
+		last = min(last, p0-pLo)
+	}
+
+	// 副軸: 移動量mが[mLo, mHi]に収まる
+	mLo, mHi := qLo-q0, qHi-q0
+	if sq < 0 {
+		mLo, mHi = q0-qHi, q0-qLo
+	}
+	if mHi < 0 || mLo > minor {
+		return 0, 0, false
+	}
+	// m(k) = floor((2k*minor + major - 1) / (2*major)) は単調増加なので、境界を逆算する
+	m2 := 2 * int64(major)
+	n2 := 2 * int64(minor)
+	if mLo > 0 {
+		first = max(first, int(ceilDiv(int64(mLo)*m2-int64(major)+1, n2)))
+	}
+	last = min(last, int(ceilDiv(int64(mHi+1)*m2-int64(major)+1, n2))-1)
+
+	return first, last, first <= last
+}
+
+// ceilDiv 切り上げの割り算（bは正）
+func ceilDiv(a, b int64) int64 {
+	if a >= 0 {
+		return (a + b - 1) / b
+	}
+	return -((-a) / b)
+}
+
+// bounds 描画できる範囲（ローカル座標、x1・y1は含まない）
+func (c canvas) bounds() (x0, y0, x1, y1 int) {
+	width, height := ScreenWidth, ScreenHeight
+	switch c.mode {
+	case canvasMode4:
+		width, height = Mode4Width, Mode4Height
+	case canvasMode5:
+		width, height = Mode5Width, Mode5Height
+	}
+	x0, y0, x1, y1 = clipArea(width, height)
+	return x0 - clip.ox, y0 - clip.oy, x1 - clip.ox, y1 - clip.oy
+}
+
+// fillTriangle 三角形を塗りつぶす（スキャンライン）
+func (c canvas) fillTriangle(x0, y0, x1, y1, x2, y2 int) {
+	// yの昇順に並べる
+	if y0 > y1 {
+		x0, y0, x1, y1 = x1, y1, x0, y0
+	}
+	if y1 > y2 {
+		x1, y1, x2, y2 = x2, y2, x1, y1
+	}
+	if y0 > y1 {
+		x0, y0, x1, y1 = x1, y1, x0, y0
+	}
+
+	if y0 == y2 {
+		// 高さ0の三角形は水平線
+		c.hline(min(x0, min(x1, x2)), max(x0, max(x1, x2)), y0)
+		return
+	}
+
+	_, top, _, bottom := c.bounds()
+	yStart := max(y0, top)
+	yEnd := min(y2, bottom-1)
+	for y := yStart; y <= yEnd; y++ {
+		// 長い辺（0-2）上のx
+		xa := x0 + divRound((x2-x0)*(y-y0), y2-y0)
+		// 短い辺（0-1 または 1-2）上のx
+		var xb int
+		if y < y1 {
+			xb = x0 + divRound((x1-x0)*(y-y0), y1-y0)
+		} else if y2 != y1 {
+			xb = x1 + divRound((x2-x1)*(y-y1), y2-y1)
+		} else {
+			xb = x1
+		}
+		c.hline(xa, xb, y)
+	}
+}
+
+// divRound 整数の割り算（四捨五入）
+func divRound(a, b int) int {
+	if b < 0 {
+		a, b = -a, -b
+	}
+	if a >= 0 {
+		return (a + b/2) / b
+	}
+	return -((-a + b/2) / b)
+}
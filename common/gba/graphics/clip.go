@@ -0,0 +1,137 @@
+package graphics
+
+// クリップ矩形と原点（ビューポート）
+// すべての描画関数は座標に原点を加えてから、クリップ矩形の外を描画しない
+// パネルや分割画面をローカル座標で描き、隣の領域にはみ出さないようにするために使う
+
+// MaxClipDepth クリップのスタックの深さ
+const MaxClipDepth = 8
+
+// clipState クリップ矩形（画面座標、x1・y1は含まない）と原点
+type clipState struct {
+	x0, y0, x1, y1 int
+	ox, oy         int
+}
+
+// fullClip 画面全体（原点は左上）
+var fullClip = clipState{x0: 0, y0: 0, x1: ScreenWidth, y1: ScreenHeight}
+
+var (
+	clip       = fullClip // 現在のクリップ
+	clipStack  [MaxClipDepth]clipState
+	clipDepth  int
+	clipIsFull = true // クリップなし・原点移動なしの状態か（高速化用）
+)
+
+// PushClip 現在のクリップを保存し、矩形（ローカル座標）で絞り込む
+// スタックが一杯ならfalseを返し、何もしない
+func PushClip(x, y, width, height int) bool {
+	if !pushClip() {
+		return false
+	}
+	x += clip.ox
+	y += clip.oy
+	clip.x0 = max(clip.x0, x)
+	clip.y0 = max(clip.y0, y)
+	clip.x1 = min(clip.x1, x+width)
+	clip.y1 = min(clip.y1, y+height)
+	updateClipIsFull()
+	return true
+}
+
+// PushViewport 矩形（ローカル座標）で絞り込み、その左上を新しい原点にする
+// 以降の描画はビューポート内の座標で行う
+func PushViewport(x, y, width, height int) bool {
+	if !PushClip(x, y, width, height) {
+		return false
+	}
+	clip.ox += x
+	clip.oy += y
+	updateClipIsFull()
+	return true
+}
+
+// PushOffset 原点だけを(dx, dy)ずらす（スクロールに使う、クリップはそのまま）
+func PushOffset(dx, dy int) bool {
+	if !pushClip() {
+		return false
+	}
+	clip.ox += dx
+	clip.oy += dy
+	updateClipIsFull()
+	return true
+}
+
+// PopClip 直前のPush前の状態に戻す
+func PopClip() {
+	if clipDepth == 0 {
+		return
+	}
+	clipDepth--
+	clip = clipStack[clipDepth]
+	updateClipIsFull()
+}
+
+// PopViewport PushViewportを取り消す（PopClipと同じ）
+func PopViewport() {
+	PopClip()
+}
+
+// ResetClip スタックを空にして画面全体に戻す
+func ResetClip() {
+	clipDepth = 0
+	clip = fullClip
+	clipIsFull = true
+}
+
+// ClipRect 現在のクリップ矩形（ローカル座標）を取得
+func ClipRect() (x, y, width, height int) {
+	width = max(clip.x1-clip.x0, 0)
+	height = max(clip.y1-clip.y0, 0)
+	return clip.x0 - clip.ox, clip.y0 - clip.oy, width, height
+}
+
+// Origin 現在の原点（画面座標）を取得
+func Origin() (x, y int) {
+	return clip.ox, clip.oy
+}
+
+// pushClip 現在の状態をスタックに積む
+func pushClip() bool {
+	if clipDepth >= MaxClipDepth {
+		return false
+	}
+	clipStack[clipDepth] = clip
+	clipDepth++
+	return true
+}
+
+// updateClipIsFull clipIsFullを更新
+func updateClipIsFull() {
+	clipIsFull = clip == fullClip
+}
+
+// clipArea 画面サイズ(width, height)のモードでの描画可能範囲（画面座標）
+func clipArea(width, height int) (x0, y0, x1, y1 int) {
+	return clip.x0, clip.y0, min(clip.x1, width), min(clip.y1, height)
+}
+
+// clipSpan ローカル座標の矩形を画面座標に変換してクリップする
+// 描画する範囲がなければok=false
+func clipSpan(x, y, width, height, screenW, screenH int) (cx, cy, cw, ch int, ok bool) {
+	x0, y0, x1, y1 := clipArea(screenW, screenH)
+	x += clip.ox
+	y += clip.oy
+	cx = max(x, x0)
+	cy = max(y, y0)
+	cw = min(x+width, x1) - cx
+	ch = min(y+height, y1) - cy
+	return cx, cy, cw, ch, cw > 0 && ch > 0
+}
+
+// clipPoint ローカル座標の点を画面座標に変換し、クリップ内か判定
+func clipPoint(x, y, screenW, screenH int) (int, int, bool) {
+	x += clip.ox
+	y += clip.oy
+	return x, y, x >= clip.x0 && x < min(clip.x1, screenW) && y >= clip.y0 && y < min(clip.y1, screenH)
+}
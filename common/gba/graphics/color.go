@@ -1,5 +1,7 @@
 package graphics
 
+import "github.com/ryomak/gameboys/common/math"
+
 // RGB15 RGB値から15bitカラーに変換（各色5bit: 0-31）
 func RGB15(r, g, b uint8) uint16 {
 	return uint16(r&0x1F) | (uint16(g&0x1F) << 5) | (uint16(b&0x1F) << 10)
@@ -11,7 +13,11 @@ func RGB8to5(value uint8) uint8 {
 }
 
 // RGB RGB値（0-255）から15bitカラーに変換
+// SetLCDCorrection(true)の場合はLCDガンマ補正をかける
 func RGB(r, g, b uint8) uint16 {
+	if lcdCorrection {
+		return RGB15(lcdGammaTable[RGB8to5(r)], lcdGammaTable[RGB8to5(g)], lcdGammaTable[RGB8to5(b)])
+	}
 	return RGB15(RGB8to5(r), RGB8to5(g), RGB8to5(b))
 }
 
@@ -41,3 +47,268 @@ var (
 	ColorDarkGray = RGB15(7, 7, 7)
 	ColorLightGray = RGB15(23, 23, 23)
 )
+
+// LCDガンマ補正
+// GBAの液晶はPCモニタより暗く見える（ガンマ約4.0 / PCは約2.2）ため、
+// PCで作った色を明るく持ち上げて実機で同じ見た目になるようにする
+// lcdGammaTable[i] = round(31 * (i/31)^(2.2/4.0))
+var lcdGammaTable = [32]uint8{
+	0, 5, 7, 9, 10, 11, 13, 14, 15, 16, 17, 18, 18, 19, 20, 21,
+	22, 22, 23, 24, 24, 25, 26, 26, 27, 28, 28, 29, 29, 30, 30, 31,
+}
+
+// lcdCorrection RGB()でLCDガンマ補正を行うか
+var lcdCorrection = false
+
+// SetLCDCorrection RGB()でのLCDガンマ補正を有効/無効にする
+// 有効にする前にRGB()で作った色（パッケージ変数の初期化など）には適用されない
+func SetLCDCorrection(enabled bool) {
+	lcdCorrection = enabled
+}
+
+// IsLCDCorrection LCDガンマ補正が有効か
+func IsLCDCorrection() bool {
+	return lcdCorrection
+}
+
+// CorrectRGB15 15bitカラーにLCDガンマ補正をかける
+func CorrectRGB15(color uint16) uint16 {
+	r, g, b := ExtractRGB(color)
+	return RGB15(lcdGammaTable[r], lcdGammaTable[g], lcdGammaTable[b])
+}
+
+// clamp5 0-31の範囲に制限
+func clamp5(v int32) uint8 {
+	if v < 0 {
+		return 0
+	}
+	if v > 31 {
+		return 31
+	}
+	return uint8(v)
+}
+
+// BlendRGB15 2色をハードウェアのアルファブレンドと同じ式で合成
+// eva, evb: 係数（0-16、16で1.0）。結果 = min(31, a*eva/16 + b*evb/16)
+func BlendRGB15(a, b uint16, eva, evb uint8) uint16 {
+	ar, ag, ab := ExtractRGB(a)
+	br, bg, bb := ExtractRGB(b)
+	r := (int32(ar)*int32(eva) + int32(br)*int32(evb)) >> 4
+	g := (int32(ag)*int32(eva) + int32(bg)*int32(evb)) >> 4
+	bl := (int32(ab)*int32(eva) + int32(bb)*int32(evb)) >> 4
+	return RGB15(clamp5(r), clamp5(g), clamp5(bl))
+}
+
+// LerpRGB15 2色を線形補間（t: 0.0-1.0、0でa、1でb）
+func LerpRGB15(a, b uint16, t math.Fixed) uint16 {
+	if t <= 0 {
+		return a
+	}
+	if t >= math.FixedOne {
+		return b
+	}
+	ar, ag, ab := ExtractRGB(a)
+	br, bg, bb := ExtractRGB(b)
+	r := int32(ar) + math.NewFixed(int32(br)-int32(ar)).Mul(t).Round()
+	g := int32(ag) + math.NewFixed(int32(bg)-int32(ag)).Mul(t).Round()
+	bl := int32(ab) + math.NewFixed(int32(bb)-int32(ab)).Mul(t).Round()
+	return RGB15(clamp5(r), clamp5(g), clamp5(bl))
+}
+
+// BrightenRGB15 白に向かって明るくする（t: 0.0-1.0、ハードウェアの輝度上昇と同じ考え方）
+func BrightenRGB15(color uint16, t math.Fixed) uint16 {
+	return LerpRGB15(color, ColorWhite, t)
+}
+
+// DarkenRGB15 黒に向かって暗くする（t: 0.0-1.0）
+func DarkenRGB15(color uint16, t math.Fixed) uint16 {
+	return LerpRGB15(color, ColorBlack, t)
+}
+
+// Gradient fromからtoまでのグラデーションをdstに書き込む（両端を含む）
+// パレット範囲のスライスに書き込めばグラデーションパレットになる
+func Gradient(dst []uint16, from, to uint16) {
+	n := len(dst)
+	if n == 0 {
+		return
+	}
+	if n == 1 {
+		dst[0] = from
+		return
+	}
+	den := math.NewFixed(int32(n - 1))
+	for i := 0; i < n; i++ {
+		dst[i] = LerpRGB15(from, to, math.NewFixed(int32(i)).Div(den))
+	}
+}
+
+// hueOf 5bitのRGB値から色相（0-255）を求める
+func hueOf(r, g, b, maxC, delta int32) int32 {
+	if delta == 0 {
+		return 0
+	}
+	// 6分割した色相の区間（0-6）をdelta単位で求める
+	var h6 int32
+	switch maxC {
+	case r:
+		h6 = g - b
+	case g:
+		h6 = 2*delta + (b - r)
+	default:
+		h6 = 4*delta + (r - g)
+	}
+	hue := (h6*math.AngleMax + 3*delta) / (6 * delta)
+	return hue & (math.AngleMax - 1)
+}
+
+// minmax3 3値の最小値と最大値
+func minmax3(a, b, c int32) (lo, hi int32) {
+	lo, hi = a, a
+	if b < lo {
+		lo = b
+	}
+	if b > hi {
+		hi = b
+	}
+	if c < lo {
+		lo = c
+	}
+	if c > hi {
+		hi = c
+	}
+	return
+}
+
+// RGB15ToHSV 15bitカラーをHSVに変換
+// h: 色相（0-255 が 0-360度、math.Sin/Cosと同じ形式）、s, v: 0.0-1.0
+func RGB15ToHSV(color uint16) (h int32, s, v math.Fixed) {
+	r8, g8, b8 := ExtractRGB(color)
+	r, g, b := int32(r8), int32(g8), int32(b8)
+	lo, hi := minmax3(r, g, b)
+	delta := hi - lo
+
+	v = math.NewFixed(hi).Div(math.NewFixed(31))
+	if hi > 0 {
+		s = math.NewFixed(delta).Div(math.NewFixed(hi))
+	}
+	h = hueOf(r, g, b, hi, delta)
+	return
+}
+
+// hueToRGB 色相と彩度（クロマ）から各成分を求める
+// c: クロマ、m: 明度の底上げ分（いずれも0.0-1.0）
+func hueToRGB(h int32, c, m math.Fixed) uint16 {
+	h = h & (math.AngleMax - 1)
+	// 色相を6区間に分割し、区間内の位置を0.0-1.0で求める
+	h6 := h * 6
+	sector := h6 / math.AngleMax
+	frac := math.Fixed((h6 % math.AngleMax) << (math.FixedShift - 8))
+
+	// x = c * (1 - |(h/60 mod 2) - 1|)
+	var x math.Fixed
+	if sector%2 == 0 {
+		x = c.Mul(frac)
+	} else {
+		x = c.Mul(math.FixedOne - frac)
+	}
+
+	var r, g, b math.Fixed
+	switch sector {
+	case 0:
+		r, g, b = c, x, 0
+	case 1:
+		r, g, b = x, c, 0
+	case 2:
+		r, g, b = 0, c, x
+	case 3:
+		r, g, b = 0, x, c
+	case 4:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+
+	scale := math.NewFixed(31)
+	return RGB15(
+		clamp5(r.Add(m).Mul(scale).Round()),
+		clamp5(g.Add(m).Mul(scale).Round()),
+		clamp5(b.Add(m).Mul(scale).Round()),
+	)
+}
+
+// HSVToRGB15 HSVから15bitカラーに変換
+// h: 色相（0-255）、s, v: 0.0-1.0
+func HSVToRGB15(h int32, s, v math.Fixed) uint16 {
+	s = s.Clamp(0, math.FixedOne)
+	v = v.Clamp(0, math.FixedOne)
+	c := v.Mul(s)
+	return hueToRGB(h, c, v.Sub(c))
+}
+
+// RGB15ToHSL 15bitカラーをHSLに変換
+// h: 色相（0-255）、s, l: 0.0-1.0
+func RGB15ToHSL(color uint16) (h int32, s, l math.Fixed) {
+	r8, g8, b8 := ExtractRGB(color)
+	r, g, b := int32(r8), int32(g8), int32(b8)
+	lo, hi := minmax3(r, g, b)
+	delta := hi - lo
+
+	// l = (max + min) / 2 / 31
+	l = math.NewFixed(hi + lo).Div(math.NewFixed(62))
+	if delta > 0 {
+		// s = delta / (1 - |2l - 1|)
+		den := hi + lo
+		if den > 31 {
+			den = 62 - den
+		}
+		s = math.NewFixed(delta).Div(math.NewFixed(den))
+	}
+	h = hueOf(r, g, b, hi, delta)
+	return
+}
+
+// HSLToRGB15 HSLから15bitカラーに変換
+// h: 色相（0-255）、s, l: 0.0-1.0
+func HSLToRGB15(h int32, s, l math.Fixed) uint16 {
+	s = s.Clamp(0, math.FixedOne)
+	l = l.Clamp(0, math.FixedOne)
+	// c = (1 - |2l - 1|) * s
+	c := math.FixedOne.Sub(l.Mul(math.NewFixed(2)).Sub(math.FixedOne).Abs()).Mul(s)
+	return hueToRGB(h, c, l.Sub(c>>1))
+}
+
+// bayer4x4 4x4のBayer行列（0-15）
+var bayer4x4 = [4][4]uint8{
+	{0, 8, 2, 10},
+	{12, 4, 14, 6},
+	{3, 11, 1, 9},
+	{15, 7, 13, 5},
+}
+
+// BayerThreshold 座標に対応するBayer行列の値（0-15）
+func BayerThreshold(x, y int) uint8 {
+	return bayer4x4[y&3][x&3]
+}
+
+// ditherChannel 8bit値に閾値を足して5bitに落とす
+func ditherChannel(value uint8, threshold uint8) uint8 {
+	// 5bitの1段階（8）を16分割した閾値を加える
+	v := int32(value) + int32(threshold)/2
+	if v > 255 {
+		v = 255
+	}
+	return uint8(v) >> 3
+}
+
+// DitherRGB 24bitカラーを組織的ディザリングで15bitカラーに変換
+// (x, y): 描画先の座標（ディザパターンの位置を決める）
+func DitherRGB(x, y int, r, g, b uint8) uint16 {
+	t := BayerThreshold(x, y)
+	r5 := ditherChannel(r, t)
+	g5 := ditherChannel(g, t)
+	b5 := ditherChannel(b, t)
+	if lcdCorrection {
+		return RGB15(lcdGammaTable[r5], lcdGammaTable[g5], lcdGammaTable[b5])
+	}
+	return RGB15(r5, g5, b5)
+}
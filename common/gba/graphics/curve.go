@@ -0,0 +1,121 @@
+package graphics
+
+import "github.com/ryomak/gameboys/common/math"
+
+// MaxCurveSegments ベジェ曲線を折れ線に分割するときの最大分割数
+const MaxCurveSegments = 32
+
+// DrawThickLine 太さを指定して線を描画（端は線に垂直に切る）
+func DrawThickLine(x0, y0, x1, y1, thickness int, color uint16) {
+	mode3Canvas(color).thickLine(x0, y0, x1, y1, thickness)
+}
+
+// DrawThickLineMode4 Mode 4で太さを指定して線を描画
+func DrawThickLineMode4(x0, y0, x1, y1, thickness int, colorIndex uint8) {
+	mode4Canvas(colorIndex).thickLine(x0, y0, x1, y1, thickness)
+}
+
+// thickLine 太い線を2つの三角形（平行四辺形）として描画
+func (c canvas) thickLine(x0, y0, x1, y1, thickness int) {
+	if thickness <= 1 {
+		c.line(x0, y0, x1, y1)
+		return
+	}
+
+	dx := x1 - x0
+	dy := y1 - y0
+	length := int(math.IntSqrt(int32(dx*dx + dy*dy)))
+	if length == 0 {
+		// 点の場合は正方形
+		half := (thickness - 1) / 2
+		for row := y0 - half; row < y0-half+thickness; row++ {
+			c.hline(x0-half, x0-half+thickness-1, row)
+		}
+		return
+	}
+
+	// 線に垂直な方向の幅（thickness-1ピクセル分）のベクトル
+	wx := divRound(-dy*(thickness-1), length)
+	wy := divRound(dx*(thickness-1), length)
+	// 中心線の両側に振り分ける
+	ax := wx / 2
+	ay := wy / 2
+	bx := ax - wx
+	by := ay - wy
+
+	c.fillTriangle(x0+ax, y0+ay, x1+ax, y1+ay, x1+bx, y1+by)
+	c.fillTriangle(x0+ax, y0+ay, x1+bx, y1+by, x0+bx, y0+by)
+	// 三角形の境界の取りこぼしを防ぐため両辺を線でなぞる
+	c.line(x0+ax, y0+ay, x1+ax, y1+ay)
+	c.line(x0+bx, y0+by, x1+bx, y1+by)
+}
+
+// DrawQuadBezier 2次ベジェ曲線を描画
+// (x0, y0): 始点、(x1, y1): 制御点、(x2, y2): 終点
+func DrawQuadBezier(x0, y0, x1, y1, x2, y2 int, color uint16) {
+	mode3Canvas(color).quadBezier(x0, y0, x1, y1, x2, y2)
+}
+
+// DrawCubicBezier 3次ベジェ曲線を描画
+// (x0, y0): 始点、(x1, y1)と(x2, y2): 制御点、(x3, y3): 終点
+func DrawCubicBezier(x0, y0, x1, y1, x2, y2, x3, y3 int, color uint16) {
+	mode3Canvas(color).cubicBezier(x0, y0, x1, y1, x2, y2, x3, y3)
+}
+
+// DrawQuadBezierMode4 Mode 4で2次ベジェ曲線を描画
+func DrawQuadBezierMode4(x0, y0, x1, y1, x2, y2 int, colorIndex uint8) {
+	mode4Canvas(colorIndex).quadBezier(x0, y0, x1, y1, x2, y2)
+}
+
+// DrawCubicBezierMode4 Mode 4で3次ベジェ曲線を描画
+func DrawCubicBezierMode4(x0, y0, x1, y1, x2, y2, x3, y3 int, colorIndex uint8) {
+	mode4Canvas(colorIndex).cubicBezier(x0, y0, x1, y1, x2, y2, x3, y3)
+}
+
+// curveSegments 制御点の折れ線の長さから分割数を決める（約4ピクセルごと）
+func curveSegments(length int) int {
+	n := length / 4
+	if n < 2 {
+		return 2
+	}
+	if n > MaxCurveSegments {
+		return MaxCurveSegments
+	}
+	return n
+}
+
+// quadBezier 2次ベジェ曲線を折れ線で描画
+// B(t) = (1-t)^2 P0 + 2(1-t)t P1 + t^2 P2 を t=i/n として整数で計算する
+func (c canvas) quadBezier(x0, y0, x1, y1, x2, y2 int) {
+	n := curveSegments(abs(x1-x0) + abs(y1-y0) + abs(x2-x1) + abs(y2-y1))
+	nn := n * n
+
+	prevX, prevY := x0, y0
+	for i := 1; i <= n; i++ {
+		s := n - i
+		x := divRound(s*s*x0+2*s*i*x1+i*i*x2, nn)
+		y := divRound(s*s*y0+2*s*i*y1+i*i*y2, nn)
+		c.line(prevX, prevY, x, y)
+		prevX, prevY = x, y
+	}
+}
+
+// cubicBezier 3次ベジェ曲線を折れ線で描画
+// B(t) = (1-t)^3 P0 + 3(1-t)^2 t P1 + 3(1-t)t^2 P2 + t^3 P3
+func (c canvas) cubicBezier(x0, y0, x1, y1, x2, y2, x3, y3 int) {
+	n := curveSegments(abs(x1-x0) + abs(y1-y0) + abs(x2-x1) + abs(y2-y1) + abs(x3-x2) + abs(y3-y2))
+	nnn := n * n * n
+
+	prevX, prevY := x0, y0
+	for i := 1; i <= n; i++ {
+		s := n - i
+		k0 := s * s * s
+		k1 := 3 * s * s * i
+		k2 := 3 * s * i * i
+		k3 := i * i * i
+		x := divRound(k0*x0+k1*x1+k2*x2+k3*x3, nnn)
+		y := divRound(k0*y0+k1*y1+k2*y2+k3*y3, nnn)
+		c.line(prevX, prevY, x, y)
+		prevX, prevY = x, y
+	}
+}
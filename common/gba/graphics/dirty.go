@@ -0,0 +1,238 @@
+package graphics
+
+import (
+	"unsafe"
+
+	"github.com/ryomak/gameboys/common/gba/memory"
+	"github.com/ryomak/gameboys/common/util"
+)
+
+// Mode 3用の差分描画（ダーティ矩形）
+// 変化した領域だけ背景レイヤーから復元し、その領域に重なるオブジェクトだけを再描画する
+
+const (
+	MaxDirtyRects   = 32 // 1フレームで保持するダーティ矩形の最大数
+	MaxDirtyObjects = 32 // 登録できるオブジェクトの最大数
+)
+
+// DirtyObject 差分描画の対象オブジェクト
+type DirtyObject interface {
+	// Bounds 現在の描画範囲（画面座標）。Drawはこの範囲の外に描画しないこと
+	Bounds() util.Rect
+	// Draw 現在の状態で描画
+	Draw()
+}
+
+// DirtyRenderer ダーティ矩形による差分描画
+type DirtyRenderer struct {
+	background  [ScreenWidth * ScreenHeight]uint16 // 背景レイヤー
+	rects       [MaxDirtyRects]util.Rect
+	rectCount   int
+	objects     [MaxDirtyObjects]DirtyObject
+	lastBounds  [MaxDirtyObjects]util.Rect // 前回描画した範囲
+	drawn       [MaxDirtyObjects]bool      // lastBoundsが有効か
+	objectCount int
+	screen      *[ScreenWidth * ScreenHeight]uint16 // BeginBackground中の本来の描画先
+}
+
+// NewDirtyRenderer 差分描画を初期化
+// 背景レイヤー（約75KB）を含むため、IWRAMではなくヒープ（EWRAM）に確保する
+func NewDirtyRenderer() *DirtyRenderer {
+	return &DirtyRenderer{}
+}
+
+// screenRect 画面全体の矩形
+var screenRect = util.NewRect(0, 0, ScreenWidth, ScreenHeight)
+
+// CaptureBackground 現在の画面を背景レイヤーとして保存
+func (r *DirtyRenderer) CaptureBackground() {
+	memory.DMA3Copy32(unsafe.Pointer(&r.background[0]), unsafe.Pointer(&VideoBuffer[0]), ScreenWidth*ScreenHeight/2)
+}
+
+// BeginBackground 描画先を背景レイヤーに切り替える
+// EndBackgroundまでのMode 3描画関数は背景レイヤーに描画される
+// 描画した範囲はInvalidateで画面に反映させる
+func (r *DirtyRenderer) BeginBackground() {
+	if r.screen != nil {
+		return
+	}
+	r.screen = VideoBuffer
+	VideoBuffer = &r.background
+}
+
+// EndBackground 描画先を画面に戻す
+func (r *DirtyRenderer) EndBackground() {
+	if r.screen == nil {
+		return
+	}
+	VideoBuffer = r.screen
+	r.screen = nil
+}
+
+// Add オブジェクトを登録（空きがなければfalse）
+// 登録順に描画されるため、手前に表示するものを後から登録する
+func (r *DirtyRenderer) Add(obj DirtyObject) bool {
+	if r.objectCount >= MaxDirtyObjects {
+		return false
+	}
+	r.objects[r.objectCount] = obj
+	r.drawn[r.objectCount] = false
+	r.objectCount++
+	return true
+}
+
+// Remove オブジェクトの登録を解除（描画されていた範囲は背景に戻る）
+func (r *DirtyRenderer) Remove(obj DirtyObject) {
+	for i := 0; i < r.objectCount; i++ {
+		if r.objects[i] != obj {
+			continue
+		}
+		if r.drawn[i] {
+			r.Invalidate(r.lastBounds[i])
+		}
+		copy(r.objects[i:r.objectCount], r.objects[i+1:r.objectCount])
+		copy(r.lastBounds[i:r.objectCount], r.lastBounds[i+1:r.objectCount])
+		copy(r.drawn[i:r.objectCount], r.drawn[i+1:r.objectCount])
+		r.objectCount--
+		r.objects[r.objectCount] = nil
+		return
+	}
+}
+
+// Invalidate 領域を再描画対象にする
+// 重なるダーティ矩形とは結合して1つの矩形にまとめる
+func (r *DirtyRenderer) Invalidate(rect util.Rect) {
+	rect = rect.Intersection(screenRect)
+	if rect.IsEmpty() {
+		return
+	}
+
+	// 重なる矩形を取り込み続ける（結合で新たに重なる矩形も取り込む）
+	for merged := true; merged; {
+		merged = false
+		for i := 0; i < r.rectCount; i++ {
+			if rect.Intersects(r.rects[i]) {
+				rect = rect.Union(r.rects[i])
+				r.removeRect(i)
+				merged = true
+				i--
+			}
+		}
+	}
+
+	if r.rectCount < MaxDirtyRects {
+		r.rects[r.rectCount] = rect
+		r.rectCount++
+		return
+	}
+
+	// 空きがない場合は、結合後の面積の増加が最小の矩形と結合する
+	best := 0
+	bestGrowth := int32(-1)
+	for i := 0; i < r.rectCount; i++ {
+		u := r.rects[i].Union(rect)
+		growth := u.Area() - r.rects[i].Area()
+		if bestGrowth < 0 || growth < bestGrowth {
+			best = i
+			bestGrowth = growth
+		}
+	}
+	merged := r.rects[best].Union(rect)
+	r.removeRect(best)
+	r.Invalidate(merged)
+}
+
+// InvalidateAll 画面全体を再描画対象にする
+func (r *DirtyRenderer) InvalidateAll() {
+	r.rects[0] = screenRect
+	r.rectCount = 1
+}
+
+// removeRect i番目のダーティ矩形を取り除く
+func (r *DirtyRenderer) removeRect(i int) {
+	r.rectCount--
+	r.rects[i] = r.rects[r.rectCount]
+}
+
+// isDirty 矩形がいずれかのダーティ矩形と重なるか
+func (r *DirtyRenderer) isDirty(rect util.Rect) bool {
+	for i := 0; i < r.rectCount; i++ {
+		if rect.Intersects(r.rects[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// isCovered 矩形がいずれかのダーティ矩形に完全に含まれるか
+func (r *DirtyRenderer) isCovered(rect util.Rect) bool {
+	for i := 0; i < r.rectCount; i++ {
+		if r.rects[i].ContainsRect(rect) {
+			return true
+		}
+	}
+	return false
+}
+
+// restore ダーティ矩形の範囲を背景レイヤーから復元
+func (r *DirtyRenderer) restore(rect util.Rect) {
+	width := int(rect.Width)
+	for y := int(rect.Y); y < int(rect.Bottom()); y++ {
+		offset := y*ScreenWidth + int(rect.X)
+		copy(VideoBuffer[offset:offset+width], r.background[offset:offset+width])
+	}
+}
+
+// Render ダーティ領域を背景から復元し、重なるオブジェクトを再描画（毎フレーム呼び出す）
+// 移動したオブジェクトは前回と今回の範囲が自動的に再描画対象になる
+func (r *DirtyRenderer) Render() {
+	// 移動・新規登録されたオブジェクトの範囲を登録
+	for i := 0; i < r.objectCount; i++ {
+		bounds := r.objects[i].Bounds().Intersection(screenRect)
+		if r.drawn[i] && bounds == r.lastBounds[i] {
+			continue
+		}
+		if r.drawn[i] {
+			r.Invalidate(r.lastBounds[i])
+		}
+		r.Invalidate(bounds)
+	}
+
+	if r.rectCount == 0 {
+		return
+	}
+
+	// ダーティ領域に一部だけ重なるオブジェクトは、全体を再描画対象に広げる
+	// （重なり合うオブジェクトの描画順を保つため、変化がなくなるまで繰り返す）
+	for pass := 0; pass <= r.objectCount; pass++ {
+		grown := false
+		for i := 0; i < r.objectCount; i++ {
+			bounds := r.objects[i].Bounds().Intersection(screenRect)
+			if bounds.IsEmpty() || !r.isDirty(bounds) || r.isCovered(bounds) {
+				continue
+			}
+			r.Invalidate(bounds)
+			grown = true
+		}
+		if !grown {
+			break
+		}
+	}
+
+	// 背景を復元
+	for i := 0; i < r.rectCount; i++ {
+		r.restore(r.rects[i])
+	}
+
+	// 重なるオブジェクトを登録順に再描画
+	for i := 0; i < r.objectCount; i++ {
+		bounds := r.objects[i].Bounds().Intersection(screenRect)
+		r.lastBounds[i] = bounds
+		r.drawn[i] = true
+		if !bounds.IsEmpty() && r.isDirty(bounds) {
+			r.objects[i].Draw()
+		}
+	}
+
+	r.rectCount = 0
+}
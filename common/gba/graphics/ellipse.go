@@ -0,0 +1,83 @@
+package graphics
+
+// DrawEllipse 楕円の輪郭を描画（中点楕円描画アルゴリズム）
+// rx, ry: 横・縦の半径
+func DrawEllipse(cx, cy, rx, ry int, color uint16) {
+	mode3Canvas(color).ellipse(cx, cy, rx, ry, false)
+}
+
+// FillEllipse 塗りつぶした楕円を描画
+func FillEllipse(cx, cy, rx, ry int, color uint16) {
+	mode3Canvas(color).ellipse(cx, cy, rx, ry, true)
+}
+
+// DrawEllipseMode4 Mode 4で楕円の輪郭を描画
+func DrawEllipseMode4(cx, cy, rx, ry int, colorIndex uint8) {
+	mode4Canvas(colorIndex).ellipse(cx, cy, rx, ry, false)
+}
+
+// FillEllipseMode4 Mode 4で楕円を塗りつぶし
+func FillEllipseMode4(cx, cy, rx, ry int, colorIndex uint8) {
+	mode4Canvas(colorIndex).ellipse(cx, cy, rx, ry, true)
+}
+
+// ellipse 楕円を描画
+// 判定値は4倍して整数化している（半径が大きいと32bitを超えるためint64で計算）
+func (c canvas) ellipse(cx, cy, rx, ry int, fill bool) {
+	if rx < 0 || ry < 0 {
+		return
+	}
+	// 半径0は直線になる
+	if rx == 0 {
+		c.line(cx, cy-ry, cx, cy+ry)
+		return
+	}
+	if ry == 0 {
+		c.hline(cx-rx, cx+rx, cy)
+		return
+	}
+
+	a2 := int64(rx) * int64(rx)
+	b2 := int64(ry) * int64(ry)
+	x := 0
+	y := ry
+
+	// 領域1: 傾きが-1より緩やかな部分（xを1ずつ進める）
+	d := 4*b2 - 4*a2*int64(ry) + a2
+	for b2*int64(x) < a2*int64(y) {
+		c.ellipsePoints(cx, cy, x, y, fill)
+		if d >= 0 {
+			d += 4 * a2 * int64(-2*y+2)
+			y--
+		}
+		d += 4 * b2 * int64(2*x+3)
+		x++
+	}
+
+	// 領域2: 傾きが-1より急な部分（yを1ずつ進める）
+	d = b2*int64(2*x+1)*int64(2*x+1) + 4*a2*int64(y-1)*int64(y-1) - 4*a2*b2
+	for y >= 0 {
+		c.ellipsePoints(cx, cy, x, y, fill)
+		if d <= 0 {
+			d += 4 * b2 * int64(2*x+2)
+			x++
+		}
+		d += 4 * a2 * int64(-2*y+3)
+		y--
+	}
+}
+
+// ellipsePoints 楕円の対称な4点（塗りつぶしなら2本の水平線）を描画
+func (c canvas) ellipsePoints(cx, cy, x, y int, fill bool) {
+	if fill {
+		c.hline(cx-x, cx+x, cy+y)
+		if y != 0 {
+			c.hline(cx-x, cx+x, cy-y)
+		}
+		return
+	}
+	c.pixel(cx+x, cy+y)
+	c.pixel(cx-x, cy+y)
+	c.pixel(cx+x, cy-y)
+	c.pixel(cx-x, cy-y)
+}
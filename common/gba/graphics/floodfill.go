@@ -0,0 +1,86 @@
+package graphics
+
+// MaxFloodFillStack 塗りつぶしで保持できる未処理の開始点の数
+const MaxFloodFillStack = 256
+
+// floodSeed 塗りつぶしの開始点
+type floodSeed struct {
+	x, y int16
+}
+
+// floodStack 塗りつぶし用の作業領域（ヒープ確保を避けるため固定長）
+var floodStack [MaxFloodFillStack]floodSeed
+
+// FloodFill (x, y)と同じ色でつながった領域を塗りつぶす（スキャンライン方式、4近傍）
+// 塗りつぶしはクリップ矩形の中で止まる
+// 作業領域が足りず塗り残しが出た場合はfalseを返す
+func FloodFill(x, y int, color uint16) bool {
+	return mode3Canvas(color).floodFill(x, y)
+}
+
+// FloodFillMode4 Mode 4で領域を塗りつぶす
+func FloodFillMode4(x, y int, colorIndex uint8) bool {
+	return mode4Canvas(colorIndex).floodFill(x, y)
+}
+
+// floodFill 領域を塗りつぶす
+func (c canvas) floodFill(x, y int) bool {
+	bx0, by0, bx1, by1 := c.bounds()
+	if x < bx0 || x >= bx1 || y < by0 || y >= by1 {
+		return true
+	}
+	target := c.get(x, y)
+	if target == c.color {
+		return true
+	}
+
+	complete := true
+	top := 0
+	floodStack[top] = floodSeed{int16(x), int16(y)}
+	top++
+
+	for top > 0 {
+		top--
+		sx := int(floodStack[top].x)
+		sy := int(floodStack[top].y)
+		if c.get(sx, sy) != target {
+			continue // 既に塗られている
+		}
+
+		// 左右に広げて区間を求めて塗る
+		left := sx
+		for left > bx0 && c.get(left-1, sy) == target {
+			left--
+		}
+		right := sx
+		for right < bx1-1 && c.get(right+1, sy) == target {
+			right++
+		}
+		c.hline(left, right, sy)
+
+		// 上下の行で、塗る対象の連続区間ごとに開始点を1つ積む
+		for _, ny := range [2]int{sy - 1, sy + 1} {
+			if ny < by0 || ny >= by1 {
+				continue
+			}
+			inRun := false
+			for nx := left; nx <= right; nx++ {
+				if c.get(nx, ny) != target {
+					inRun = false
+					continue
+				}
+				if inRun {
+					continue
+				}
+				inRun = true
+				if top >= MaxFloodFillStack {
+					complete = false
+					continue
+				}
+				floodStack[top] = floodSeed{int16(nx), int16(ny)}
+				top++
+			}
+		}
+	}
+	return complete
+}
@@ -0,0 +1,102 @@
+package graphics
+
+import (
+	"runtime/volatile"
+	"unsafe"
+)
+
+// Mode 5: 16bitカラー、160x128、ダブルバッファリング対応
+// フレームの切り替えはMode 4と共通（SwapBuffers、GetCurrentDrawBuffer）
+
+const (
+	Mode5Width  = 160
+	Mode5Height = 128
+)
+
+// GetMode5BackBuffer 現在のバックバッファ（描画先）のアドレスを取得
+func GetMode5BackBuffer() uintptr {
+	return GetMode4BackBuffer()
+}
+
+// mode5Ptr 画面座標のピクセルへのポインタ
+func mode5Ptr(x, y int) *volatile.Register16 {
+	return (*volatile.Register16)(unsafe.Pointer(GetMode5BackBuffer() + uintptr(y*Mode5Width+x)*2))
+}
+
+// SetMode5Pixel Mode 5でピクセルを設定（バックバッファに描画）
+func SetMode5Pixel(x, y int, color uint16) {
+	x, y, ok := clipPoint(x, y, Mode5Width, Mode5Height)
+	if !ok {
+		return
+	}
+	mode5Ptr(x, y).Set(color)
+}
+
+// GetMode5Pixel Mode 5のピクセルを取得（バックバッファから、クリップ外は0）
+func GetMode5Pixel(x, y int) uint16 {
+	x, y, ok := clipPoint(x, y, Mode5Width, Mode5Height)
+	if !ok {
+		return 0
+	}
+	return mode5Ptr(x, y).Get()
+}
+
+// DrawPixelMode5 Mode 5でピクセルを描画
+func DrawPixelMode5(x, y int, color uint16) {
+	SetMode5Pixel(x, y, color)
+}
+
+// ClearMode5Screen Mode 5の画面全体をクリア（クリップ中はクリップ矩形だけ）
+func ClearMode5Screen(color uint16) {
+	x, y, w, h := ClipRect()
+	FillRectMode5(x, y, w, h, color)
+}
+
+// FillRectMode5 Mode 5で矩形を塗りつぶし
+func FillRectMode5(x, y, width, height int, color uint16) {
+	x, y, width, height, ok := clipSpan(x, y, width, height, Mode5Width, Mode5Height)
+	if !ok {
+		return
+	}
+
+	addr := GetMode5BackBuffer()
+
+	for row := 0; row < height; row++ {
+		offset := uintptr((y+row)*Mode5Width+x) * 2
+		for col := 0; col < width; col++ {
+			ptr := (*volatile.Register16)(unsafe.Pointer(addr + offset + uintptr(col)*2))
+			ptr.Set(color)
+		}
+	}
+}
+
+// DrawLineMode5 Mode 5で直線を描画
+func DrawLineMode5(x0, y0, x1, y1 int, color uint16) {
+	mode5Canvas(color).line(x0, y0, x1, y1)
+}
+
+// DrawRectMode5 Mode 5で矩形の枠を描画
+func DrawRectMode5(x, y, width, height int, color uint16) {
+	if width <= 0 || height <= 0 {
+		return
+	}
+	FillRectMode5(x, y, width, 1, color)
+	FillRectMode5(x, y+height-1, width, 1, color)
+	FillRectMode5(x, y, 1, height, color)
+	FillRectMode5(x+width-1, y, 1, height, color)
+}
+
+// DrawCircleMode5 Mode 5で円の輪郭を描画
+func DrawCircleMode5(cx, cy, radius int, color uint16) {
+	mode5Canvas(color).ellipse(cx, cy, radius, radius, false)
+}
+
+// FillCircleMode5 Mode 5で円を塗りつぶし
+func FillCircleMode5(cx, cy, radius int, color uint16) {
+	mode5Canvas(color).ellipse(cx, cy, radius, radius, true)
+}
+
+// DrawTextMode5 Mode 5で文字列を描画
+func DrawTextMode5(x, y int, text string, color uint16) {
+	mode5Canvas(color).text(x, y, text, 1)
+}
@@ -13,21 +13,26 @@ var VideoBuffer = (*[ScreenWidth * ScreenHeight]uint16)(unsafe.Pointer(uintptr(V
 
 // DrawPixel ピクセルを描画（Mode 3用）
 func DrawPixel(x, y int, color uint16) {
-	if x >= 0 && x < ScreenWidth && y >= 0 && y < ScreenHeight {
+	if x, y, ok := clipPoint(x, y, ScreenWidth, ScreenHeight); ok {
 		VideoBuffer[y*ScreenWidth+x] = color
 	}
 }
 
-// GetPixel ピクセルの色を取得（Mode 3用）
+// GetPixel ピクセルの色を取得（Mode 3用、クリップ外は0）
 func GetPixel(x, y int) uint16 {
-	if x >= 0 && x < ScreenWidth && y >= 0 && y < ScreenHeight {
+	if x, y, ok := clipPoint(x, y, ScreenWidth, ScreenHeight); ok {
 		return VideoBuffer[y*ScreenWidth+x]
 	}
 	return 0
 }
 
-// ClearScreen 画面をクリア
+// ClearScreen 画面をクリア（クリップ中はクリップ矩形だけ）
 func ClearScreen(color uint16) {
+	if !clipIsFull {
+		x, y, w, h := ClipRect()
+		FillRect(x, y, w, h, color)
+		return
+	}
 	for i := range VideoBuffer {
 		VideoBuffer[i] = color
 	}
@@ -36,21 +41,8 @@ func ClearScreen(color uint16) {
 // FillRect 矩形を塗りつぶす
 func FillRect(x, y, width, height int, color uint16) {
 	// クリッピング
-	if x < 0 {
-		width += x
-		x = 0
-	}
-	if y < 0 {
-		height += y
-		y = 0
-	}
-	if x+width > ScreenWidth {
-		width = ScreenWidth - x
-	}
-	if y+height > ScreenHeight {
-		height = ScreenHeight - y
-	}
-	if width <= 0 || height <= 0 {
+	x, y, width, height, ok := clipSpan(x, y, width, height, ScreenWidth, ScreenHeight)
+	if !ok {
 		return
 	}
 
@@ -63,17 +55,8 @@ func FillRect(x, y, width, height int, color uint16) {
 
 // DrawHLine 水平線を描画
 func DrawHLine(x, y, length int, color uint16) {
-	if y < 0 || y >= ScreenHeight {
-		return
-	}
-	if x < 0 {
-		length += x
-		x = 0
-	}
-	if x+length > ScreenWidth {
-		length = ScreenWidth - x
-	}
-	if length <= 0 {
+	x, y, length, _, ok := clipSpan(x, y, length, 1, ScreenWidth, ScreenHeight)
+	if !ok {
 		return
 	}
 
@@ -85,17 +68,8 @@ func DrawHLine(x, y, length int, color uint16) {
 
 // DrawVLine 垂直線を描画
 func DrawVLine(x, y, length int, color uint16) {
-	if x < 0 || x >= ScreenWidth {
-		return
-	}
-	if y < 0 {
-		length += y
-		y = 0
-	}
-	if y+length > ScreenHeight {
-		length = ScreenHeight - y
-	}
-	if length <= 0 {
+	x, y, _, length, ok := clipSpan(x, y, 1, length, ScreenWidth, ScreenHeight)
+	if !ok {
 		return
 	}
 
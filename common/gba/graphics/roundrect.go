@@ -0,0 +1,87 @@
+package graphics
+
+// DrawRoundRect 角の丸い矩形の枠を描画
+// radius: 角の半径（幅・高さの半分を超える場合は切り詰める）
+func DrawRoundRect(x, y, width, height, radius int, color uint16) {
+	mode3Canvas(color).roundRect(x, y, width, height, radius, false)
+}
+
+// FillRoundRect 角の丸い矩形を塗りつぶす
+func FillRoundRect(x, y, width, height, radius int, color uint16) {
+	mode3Canvas(color).roundRect(x, y, width, height, radius, true)
+}
+
+// DrawRoundRectMode4 Mode 4で角の丸い矩形の枠を描画
+func DrawRoundRectMode4(x, y, width, height, radius int, colorIndex uint8) {
+	mode4Canvas(colorIndex).roundRect(x, y, width, height, radius, false)
+}
+
+// FillRoundRectMode4 Mode 4で角の丸い矩形を塗りつぶす
+func FillRoundRectMode4(x, y, width, height, radius int, colorIndex uint8) {
+	mode4Canvas(colorIndex).roundRect(x, y, width, height, radius, true)
+}
+
+// roundRect 角の丸い矩形を描画
+func (c canvas) roundRect(x, y, width, height, radius int, fill bool) {
+	if width <= 0 || height <= 0 {
+		return
+	}
+	radius = min(radius, min((width-1)/2, (height-1)/2))
+	if radius < 0 {
+		radius = 0
+	}
+
+	// 4つの角の円の中心
+	left := x + radius
+	right := x + width - 1 - radius
+	top := y + radius
+	bottom := y + height - 1 - radius
+
+	if fill {
+		// 角の間の帯
+		for row := top; row <= bottom; row++ {
+			c.hline(x, x+width-1, row)
+		}
+	} else {
+		c.hline(left, right, y)
+		c.hline(left, right, y+height-1)
+		for row := top; row <= bottom; row++ {
+			c.pixel(x, row)
+			c.pixel(x+width-1, row)
+		}
+	}
+	if radius == 0 {
+		return
+	}
+
+	// 角の4分円（DrawCircleと同じ点列）
+	px := radius
+	py := 0
+	err := 0
+	for px >= py {
+		if fill {
+			c.hline(left-px, right+px, top-py)
+			c.hline(left-py, right+py, top-px)
+			c.hline(left-px, right+px, bottom+py)
+			c.hline(left-py, right+py, bottom+px)
+		} else {
+			c.pixel(left-px, top-py)
+			c.pixel(left-py, top-px)
+			c.pixel(right+px, top-py)
+			c.pixel(right+py, top-px)
+			c.pixel(left-px, bottom+py)
+			c.pixel(left-py, bottom+px)
+			c.pixel(right+px, bottom+py)
+			c.pixel(right+py, bottom+px)
+		}
+
+		if err <= 0 {
+			py++
+			err += 2*py + 1
+		}
+		if err > 0 {
+			px--
+			err -= 2*px + 1
+		}
+	}
+}
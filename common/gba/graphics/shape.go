@@ -1,34 +1,9 @@
 package graphics
 
 // DrawLine 線を描画（ブレゼンハムのアルゴリズム）
+// クリップ矩形の外の部分は計算で飛ばし、水平線・垂直線は専用の処理で描く
 func DrawLine(x0, y0, x1, y1 int, color uint16) {
-	dx := abs(x1 - x0)
-	dy := abs(y1 - y0)
-	sx := -1
-	if x0 < x1 {
-		sx = 1
-	}
-	sy := -1
-	if y0 < y1 {
-		sy = 1
-	}
-	err := dx - dy
-
-	for {
-		DrawPixel(x0, y0, color)
-		if x0 == x1 && y0 == y1 {
-			break
-		}
-		e2 := 2 * err
-		if e2 > -dy {
-			err -= dy
-			x0 += sx
-		}
-		if e2 < dx {
-			err += dx
-			y0 += sy
-		}
-	}
+	mode3Canvas(color).line(x0, y0, x1, y1)
 }
 
 // DrawRect 矩形の枠を描画
@@ -117,6 +92,23 @@ func DrawTriangle(x0, y0, x1, y1, x2, y2 int, color uint16) {
 	DrawLine(x2, y2, x0, y0, color)
 }
 
+// FillTriangle 塗りつぶした三角形を描画
+func FillTriangle(x0, y0, x1, y1, x2, y2 int, color uint16) {
+	mode3Canvas(color).fillTriangle(x0, y0, x1, y1, x2, y2)
+}
+
+// DrawTriangleMode4 Mode 4で三角形の枠を描画
+func DrawTriangleMode4(x0, y0, x1, y1, x2, y2 int, colorIndex uint8) {
+	DrawLineMode4(x0, y0, x1, y1, colorIndex)
+	DrawLineMode4(x1, y1, x2, y2, colorIndex)
+	DrawLineMode4(x2, y2, x0, y0, colorIndex)
+}
+
+// FillTriangleMode4 Mode 4で三角形を塗りつぶし
+func FillTriangleMode4(x0, y0, x1, y1, x2, y2 int, colorIndex uint8) {
+	mode4Canvas(colorIndex).fillTriangle(x0, y0, x1, y1, x2, y2)
+}
+
 // abs 絶対値を返す
 func abs(x int) int {
 	if x < 0 {
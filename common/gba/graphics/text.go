@@ -0,0 +1,182 @@
+package graphics
+
+// 3x5ピクセルの小さなビットマップフォント
+// 対応文字は ASCII の空白から '_' まで（英小文字は大文字として描画、未対応の文字は '?'）
+
+const (
+	FontWidth      = 3 // 1文字の幅
+	FontHeight     = 5 // 1文字の高さ
+	FontAdvance    = 4 // 次の文字までの間隔
+	FontLineHeight = 6 // 改行の間隔
+)
+
+// fontGlyphs 文字ごとのビットマップ（ASCII 32-95）
+// 上の行から3bitずつ、左端のピクセルが上位ビット
+var fontGlyphs = [64]uint16{
+	0x0000, 0x2482, 0x5A00, 0x5F7D, 0x3C9E, 0x52A5, 0x2AAB, 0x2400,
+	0x1491, 0x4494, 0x55D5, 0x05D0, 0x0014, 0x01C0, 0x0002, 0x12A4,
+	0x7B6F, 0x2C97, 0x73E7, 0x73CF, 0x5BC9, 0x79CF, 0x79EF, 0x7252,
+	0x7BEF, 0x7BCF, 0x0410, 0x0414, 0x1511, 0x0E38, 0x4454, 0x6282,
+	0x7BE3, 0x2BED, 0x6BAE, 0x3923, 0x6B6E, 0x79A7, 0x79A4, 0x396B,
+	0x5BED, 0x7497, 0x126A, 0x5BAD, 0x4927, 0x5FED, 0x6B6D, 0x2B6A,
+	0x6BA4, 0x2B73, 0x6BAD, 0x388E, 0x7492, 0x5B6F, 0x5B6A, 0x5BFD,
+	0x5AAD, 0x5A92, 0x72A7, 0x3493, 0x4889, 0x6496, 0x2A00, 0x0007,
+}
+
+// glyph 文字のビットマップを取得
+func glyph(ch byte) uint16 {
+	if ch >= 'a' && ch <= 'z' {
+		ch -= 'a' - 'A'
+	}
+	if ch < 32 || ch >= 96 {
+		ch = '?'
+	}
+	return fontGlyphs[ch-32]
+}
+
+// DrawText 文字列を描画（Mode 3用、'\n'で改行）
+func DrawText(x, y int, text string, color uint16) {
+	mode3Canvas(color).text(x, y, text, 1)
+}
+
+// DrawTextScaled 文字列を整数倍に拡大して描画
+func DrawTextScaled(x, y int, text string, scale int, color uint16) {
+	mode3Canvas(color).text(x, y, text, scale)
+}
+
+// DrawTextMode4 Mode 4で文字列を描画
+func DrawTextMode4(x, y int, text string, colorIndex uint8) {
+	mode4Canvas(colorIndex).text(x, y, text, 1)
+}
+
+// DrawTextScaledMode4 Mode 4で文字列を整数倍に拡大して描画
+func DrawTextScaledMode4(x, y int, text string, scale int, colorIndex uint8) {
+	mode4Canvas(colorIndex).text(x, y, text, scale)
+}
+
+// DrawNumber 整数を10進数で描画（文字列を作らないのでメモリ確保なし）
+func DrawNumber(x, y int, n int32, color uint16) {
+	mode3Canvas(color).number(x, y, n, 1)
+}
+
+// DrawNumberMode4 Mode 4で整数を描画
+func DrawNumberMode4(x, y int, n int32, colorIndex uint8) {
+	mode4Canvas(colorIndex).number(x, y, n, 1)
+}
+
+// DrawNumberScaledMode4 Mode 4で整数を整数倍に拡大して描画
+func DrawNumberScaledMode4(x, y int, n int32, scale int, colorIndex uint8) {
+	mode4Canvas(colorIndex).number(x, y, n, scale)
+}
+
+// TextWidth 文字列を描画したときの幅（最も長い行、拡大率1）
+func TextWidth(text string) int {
+	widest := 0
+	count := 0
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\n' {
+			count = 0
+			continue
+		}
+		count++
+		widest = max(widest, count)
+	}
+	if widest == 0 {
+		return 0
+	}
+	return widest*FontAdvance - (FontAdvance - FontWidth)
+}
+
+// TextHeight 文字列を描画したときの高さ（拡大率1）
+func TextHeight(text string) int {
+	if len(text) == 0 {
+		return 0
+	}
+	lines := 1
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\n' {
+			lines++
+		}
+	}
+	return (lines-1)*FontLineHeight + FontHeight
+}
+
+// NumberWidth 整数を描画したときの幅（拡大率1）
+func NumberWidth(n int32) int {
+	digits := 1
+	if n < 0 {
+		digits++
+	}
+	for n >= 10 || n <= -10 {
+		n /= 10
+		digits++
+	}
+	return digits*FontAdvance - (FontAdvance - FontWidth)
+}
+
+// text 文字列を描画
+func (c canvas) text(x, y int, text string, scale int) {
+	if scale < 1 {
+		scale = 1
+	}
+	cx := x
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\n' {
+			cx = x
+			y += FontLineHeight * scale
+			continue
+		}
+		c.glyph(cx, y, glyph(text[i]), scale)
+		cx += FontAdvance * scale
+	}
+}
+
+// number 整数を描画
+func (c canvas) number(x, y int, n int32, scale int) {
+	if scale < 1 {
+		scale = 1
+	}
+	var digits [11]byte
+	count := 0
+	negative := n < 0
+	for {
+		d := n % 10
+		if d < 0 {
+			d = -d
+		}
+		digits[count] = byte('0' + d)
+		count++
+		n /= 10
+		if n == 0 {
+			break
+		}
+	}
+	if negative {
+		c.glyph(x, y, glyph('-'), scale)
+		x += FontAdvance * scale
+	}
+	for i := count - 1; i >= 0; i-- {
+		c.glyph(x, y, glyph(digits[i]), scale)
+		x += FontAdvance * scale
+	}
+}
+
+// glyph 1文字を描画
+func (c canvas) glyph(x, y int, bits uint16, scale int) {
+	if bits == 0 {
+		return
+	}
+	for row := 0; row < FontHeight; row++ {
+		line := (bits >> uint((FontHeight-1-row)*FontWidth)) & 7
+		for col := 0; col < FontWidth; col++ {
+			if line&(4>>uint(col)) == 0 {
+				continue
+			}
+			px := x + col*scale
+			py := y + row*scale
+			for sy := 0; sy < scale; sy++ {
+				c.hline(px, px+scale-1, py+sy)
+			}
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package input
+
+import "github.com/ryomak/gameboys/common/gba/memory"
+
+// アクション（論理的な操作）とキー割り当て
+// ゲームはキーではなく「決定」「ジャンプ」などのアクションで入力を調べ、
+// 割り当てはプレイヤーが変更してSRAMに保存できる
+
+const (
+	MaxActions  = 16 // 定義できるアクションの数
+	MaxBindings = 4  // 1つのアクションに割り当てられるキー（同時押し）の数
+)
+
+// Action アクションの番号（ゲーム側で定数として定義する）
+type Action uint8
+
+// Binding キーの割り当て（複数のキーは同時押し、0は割り当てなし）
+type Binding uint16
+
+// ActionMap アクションとキー割り当て
+type ActionMap struct {
+	// Reserved どのアクションにも割り当てられないキー（設定画面を開くキーなど）
+	Reserved uint16
+
+	names    [MaxActions]string
+	bindings [MaxActions][MaxBindings]Binding
+	defaults [MaxActions][MaxBindings]Binding
+	count    int // 定義されているアクションの番号の最大+1
+
+	capture uint16 // CaptureBindingで押されたキー
+}
+
+// NewActionMap アクションの割り当てを作成
+func NewActionMap() *ActionMap {
+	return &ActionMap{}
+}
+
+// Define アクションを定義して初期の割り当てを設定（ResetDefaultsで戻る割り当てになる）
+func (m *ActionMap) Define(a Action, name string, bindings ...Binding) {
+	if int(a) >= MaxActions {
+		return
+	}
+	m.names[a] = name
+	m.defaults[a] = [MaxBindings]Binding{}
+	for i := 0; i < len(bindings) && i < MaxBindings; i++ {
+		m.defaults[a][i] = bindings[i]
+	}
+	m.bindings[a] = m.defaults[a]
+	m.count = max(m.count, int(a)+1)
+}
+
+// Name アクションの名前（設定画面の表示用）
+func (m *ActionMap) Name(a Action) string {
+	if int(a) >= MaxActions {
+		return ""
+	}
+	return m.names[a]
+}
+
+// Count 定義されているアクションの番号の最大+1
+func (m *ActionMap) Count() int {
+	return m.count
+}
+
+// Binding slot番目の割り当て
+func (m *ActionMap) Binding(a Action, slot int) Binding {
+	if int(a) >= MaxActions || slot < 0 || slot >= MaxBindings {
+		return 0
+	}
+	return m.bindings[a][slot]
+}
+
+// Bind slot番目の割り当てを変更（0で割り当てを外す）
+func (m *ActionMap) Bind(a Action, slot int, keys Binding) {
+	if int(a) >= MaxActions || slot < 0 || slot >= MaxBindings {
+		return
+	}
+	m.bindings[a][slot] = keys & KeyAny
+}
+
+// Keys アクションに割り当てられているキーをすべて合わせたもの（Samplerでの問い合わせなどに使う）
+func (m *ActionMap) Keys(a Action) uint16 {
+	if int(a) >= MaxActions {
+		return 0
+	}
+	keys := uint16(0)
+	for _, b := range m.bindings[a] {
+		keys |= uint16(b)
+	}
+	return keys
+}
+
+// ResetDefaults すべての割り当てをDefineで設定したものに戻す
+func (m *ActionMap) ResetDefaults() {
+	m.bindings = m.defaults
+}
+
+// IsDown アクションのいずれかの割り当てのキーがすべて押されているか
+func (m *ActionMap) IsDown(ks *KeyState, a Action) bool {
+	return m.matches(ks.current, a)
+}
+
+// IsPressed アクションが押された瞬間か（同時押しはすべてのキーがそろった瞬間）
+func (m *ActionMap) IsPressed(ks *KeyState, a Action) bool {
+	return m.matches(ks.current, a) && !m.matches(ks.previous, a)
+}
+
+// IsReleased アクションが離された瞬間か
+func (m *ActionMap) IsReleased(ks *KeyState, a Action) bool {
+	return !m.matches(ks.current, a) && m.matches(ks.previous, a)
+}
+
+// IsRepeated アクションが押された瞬間と、押し続けている間のリピートのフレームか
+func (m *ActionMap) IsRepeated(ks *KeyState, a Action) bool {
+	if int(a) >= MaxActions {
+		return false
+	}
+	for _, b := range m.bindings[a] {
+		if b != 0 && isChordDown(ks.current, b) && ks.IsRepeated(uint16(b)) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches キーの状態（KEYINPUTの値）でアクションのいずれかの割り当てが押されているか
+func (m *ActionMap) matches(keys uint16, a Action) bool {
+	if int(a) >= MaxActions {
+		return false
+	}
+	for _, b := range m.bindings[a] {
+		if b != 0 && isChordDown(keys, b) {
+			return true
+		}
+	}
+	return false
+}
+
+// isChordDown 割り当てのキーがすべて押されているか（KEYINPUTは0が押下）
+func isChordDown(keys uint16, b Binding) bool {
+	return keys&uint16(b) == 0
+}
+
+// Conflicts keysが他のアクションの割り当てかReservedとキーを共有しているか
+// 共有していると、片方を押したときにもう片方も同じフレームで反応する
+func (m *ActionMap) Conflicts(a Action, keys Binding) bool {
+	if uint16(keys)&m.Reserved != 0 {
+		return true
+	}
+	for other := 0; other < m.count; other++ {
+		if Action(other) == a {
+			continue
+		}
+		for _, b := range m.bindings[other] {
+			if b&keys != 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CaptureBinding 設定画面で、次に押されたキー（同時押し）をslot番目に割り当てる
+// 割り当てが決まるまで毎フレーム呼び、押したキーをすべて離したときにtrueを返す
+// 他のアクションやReservedとぶつかるキーは割り当てず、次の入力を待ち続ける
+// 設定画面を開いた決定キーも拾うため、すべてのキーが離されてから呼び始める
+func (m *ActionMap) CaptureBinding(ks *KeyState, a Action, slot int) bool {
+	held := ^ks.current & KeyAny
+	m.capture |= held
+	if held != 0 || m.capture == 0 {
+		return false
+	}
+	if m.Conflicts(a, Binding(m.capture)) {
+		m.capture = 0
+		return false
+	}
+	m.Bind(a, slot, Binding(m.capture))
+	m.capture = 0
+	return true
+}
+
+// CancelCapture CaptureBindingで押されたキーを捨てる
+func (m *ActionMap) CancelCapture() {
+	m.capture = 0
+}
+
+// 保存形式: "AM"、アクションの数、チェックサム、割り当て（リトルエンディアン）
+const (
+	bindingsHeader   = 4
+	BindingsSaveSize = bindingsHeader + MaxActions*MaxBindings*2
+)
+
+// MarshalBindings 割り当てをbufに書き出し、書いたバイト数を返す（bufが小さければ0）
+func (m *ActionMap) MarshalBindings(buf []byte) int {
+	size := bindingsHeader + m.count*MaxBindings*2
+	if len(buf) < size {
+		return 0
+	}
+	buf[0] = 'A'
+	buf[1] = 'M'
+	buf[2] = byte(m.count)
+	n := bindingsHeader
+	for a := 0; a < m.count; a++ {
+		for _, b := range m.bindings[a] {
+			buf[n] = byte(b)
+			buf[n+1] = byte(b >> 8)
+			n += 2
+		}
+	}
+	buf[3] = checksum(buf[bindingsHeader:n])
+	return n
+}
+
+// UnmarshalBindings MarshalBindingsで書き出した割り当てを読み込む
+// 形式・アクションの数・チェックサムが合わなければ変更せずにfalseを返す
+func (m *ActionMap) UnmarshalBindings(buf []byte) bool {
+	if len(buf) < bindingsHeader || buf[0] != 'A' || buf[1] != 'M' || int(buf[2]) != m.count {
+		return false
+	}
+	size := bindingsHeader + m.count*MaxBindings*2
+	if len(buf) < size || checksum(buf[bindingsHeader:size]) != buf[3] {
+		return false
+	}
+	n := bindingsHeader
+	for a := 0; a < m.count; a++ {
+		for i := range m.bindings[a] {
+			m.bindings[a][i] = (Binding(buf[n]) | Binding(buf[n+1])<<8) & KeyAny
+			n += 2
+		}
+	}
+	return true
+}
+
+// Save 割り当てをSRAMのoffsetに保存（BindingsSaveSizeバイトまで使う）
+func (m *ActionMap) Save(offset int) bool {
+	var buf [BindingsSaveSize]byte
+	n := m.MarshalBindings(buf[:])
+	return n > 0 && memory.SRAMWrite(offset, buf[:n]) == n
+}
+
+// Load SRAMのoffsetから割り当てを読み込む（保存されていなければ初期の割り当てのまま）
+func (m *ActionMap) Load(offset int) bool {
+	var buf [BindingsSaveSize]byte
+	n := memory.SRAMRead(offset, buf[:])
+	return m.UnmarshalBindings(buf[:n])
+}
+
+// checksum 保存データの簡単なチェックサム
+func checksum(data []byte) byte {
+	sum := byte(0x5A)
+	for _, b := range data {
+		sum = sum<<1 | sum>>7
+		sum ^= b
+	}
+	return sum
+}
@@ -0,0 +1,66 @@
+package input
+
+import "testing"
+
+// testActions テスト用のアクション（決定・戻る・終了）
+func testActions() *ActionMap {
+	m := NewActionMap()
+	m.Define(0, "Confirm", Binding(KeyA))
+	m.Define(1, "Cancel", Binding(KeyB))
+	m.Define(2, "Exit", Binding(KeyStart|KeySelect))
+	m.Reserved = KeyL
+	return m
+}
+
+func TestActionMapConflicts(t *testing.T) {
+	tests := []struct {
+		name string
+		keys uint16
+		want bool
+	}{
+		{"自分の割り当て", KeyA, false},
+		{"空いているキー", KeyR, false},
+		{"他のアクションのキー", KeyB, true},
+		{"他のアクションの同時押しと同じ", KeyStart | KeySelect, true},
+		{"他のアクションの同時押しの一部", KeySelect, true},
+		{"空いているキーとの同時押しに含む", KeyR | KeyB, true},
+		{"Reserved", KeyL, true},
+	}
+
+	m := testActions()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := m.Conflicts(0, Binding(tt.keys)); got != tt.want {
+				t.Errorf("Conflicts(%#x) = %v, want %v", tt.keys, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCaptureBindingRejectsConflicts(t *testing.T) {
+	m := testActions()
+	ks := &KeyState{current: KeyAny, previous: KeyAny}
+	capture := func(held uint16) bool {
+		ks.previous = ks.current
+		ks.current = ^held & KeyAny
+		return m.CaptureBinding(ks, 0, 0)
+	}
+
+	// 戻る（B）とぶつかるので割り当てず、待ち続ける
+	capture(KeyB)
+	if capture(0) {
+		t.Fatal("CaptureBinding accepted a key bound to another action")
+	}
+	if got := m.Binding(0, 0); got != Binding(KeyA) {
+		t.Fatalf("binding = %#x, want %#x", got, KeyA)
+	}
+
+	// 次に押した空いているキーを割り当てる
+	capture(KeyR)
+	if !capture(0) {
+		t.Fatal("CaptureBinding did not accept a free key")
+	}
+	if got := m.Binding(0, 0); got != Binding(KeyR) {
+		t.Errorf("binding = %#x, want %#x", got, KeyR)
+	}
+}
@@ -0,0 +1,148 @@
+package input
+
+// 入力履歴とコマンド（ボタンの順番・同時押し）の判定
+// キーが押された・離されたフレームを固定長のリングバッファに記録し、最新の入力から過去へさかのぼって照合する
+// 毎フレーム呼んでもメモリを確保しない
+
+// HistorySize 記録する入力の数
+const HistorySize = 32
+
+// keyEvent キーの状態が変わったフレームの記録
+type keyEvent struct {
+	pressed  uint16 // 押された瞬間のキー
+	released uint16 // 離された瞬間のキー
+	held     uint16 // 押しているキー
+	frame    uint32
+}
+
+// prev 直前のフレームで押していたキー
+func (e *keyEvent) prev() uint16 {
+	return e.held&^e.pressed | e.released
+}
+
+// History 入力履歴
+type History struct {
+	events [HistorySize]keyEvent
+	head   int // 次に書き込む位置
+	count  int
+	frame  uint32
+}
+
+// Update KeyStateの変化を記録（KeyState.Updateの後に毎フレーム呼ぶ）
+func (h *History) Update(ks *KeyState) {
+	h.frame++
+	pressed := ks.GetPressedKeys() & KeyAny
+	released := ks.GetReleasedKeys() & KeyAny
+	if pressed|released == 0 {
+		return
+	}
+	h.events[h.head] = keyEvent{
+		pressed:  pressed,
+		released: released,
+		held:     ^ks.GetCurrent() & KeyAny,
+		frame:    h.frame,
+	}
+	h.head = (h.head + 1) % HistorySize
+	if h.count < HistorySize {
+		h.count++
+	}
+}
+
+// Clear 履歴を消す（コマンドが成立した後に、同じ入力で別のコマンドが成立しないようにする）
+func (h *History) Clear() {
+	h.count = 0
+}
+
+// Frame Updateを呼んだ回数
+func (h *History) Frame() uint32 {
+	return h.frame
+}
+
+// at 新しい方からi番目の記録
+func (h *History) at(i int) *keyEvent {
+	return &h.events[(h.head-1-i+HistorySize)%HistorySize]
+}
+
+// Combo コマンド
+// 各ステップはキーの組み合わせ。方向キーを含むステップは方向キーの状態が一致したとき
+// （KeyDown|KeyRightは右下）、ボタンは含まれるボタンがすべて押されたときに成立する
+type Combo struct {
+	Steps     []uint16 // 順番に入力するキー
+	Window    int      // 最初のステップから最後のステップまでのフレーム数の上限（0で無制限）
+	Tolerance int      // 同時押しのボタンを押すずれの上限（フレーム）
+	Strict    bool     // 途中に別のキーを押したら不成立（隠しコマンド向け）
+}
+
+// Match このフレームでコマンドの最後のステップが入力され、コマンドが成立したか
+func (h *History) Match(c *Combo) bool {
+	n := len(c.Steps)
+	if n == 0 || h.count == 0 {
+		return false
+	}
+	last := h.at(0)
+	if last.frame != h.frame || !h.matchStep(0, c.Steps[n-1], c.Tolerance) {
+		return false
+	}
+
+	// 残りのステップを過去へさかのぼって探す
+	step := n - 2
+	for i := 1; i < h.count && step >= 0; i++ {
+		e := h.at(i)
+		if c.Window > 0 && int(last.frame-e.frame) > c.Window {
+			return false
+		}
+		if h.matchStep(i, c.Steps[step], c.Tolerance) {
+			step--
+			continue
+		}
+		// 次のステップの同時押しの途中で押したキーは許す
+		if c.Strict && e.pressed&^c.Steps[step+1] != 0 {
+			return false
+		}
+	}
+	return step < 0
+}
+
+// matchStep i番目の記録でステップが成立したか（その前のフレームでは成立していない）
+func (h *History) matchStep(i int, step uint16, tolerance int) bool {
+	e := h.at(i)
+	if !satisfies(e.held, step) || satisfies(e.prev(), step) {
+		return false
+	}
+	return h.withinTolerance(i, step&^KeyDPad, tolerance)
+}
+
+// satisfies 押しているキーがステップの条件を満たすか
+func satisfies(held, step uint16) bool {
+	if held&step != step {
+		return false
+	}
+	return step&KeyDPad == 0 || held&KeyDPad == step&KeyDPad
+}
+
+// withinTolerance 同時押しのボタンをすべてtolerance以内のずれで押したか
+func (h *History) withinTolerance(i int, buttons uint16, tolerance int) bool {
+	if buttons&(buttons-1) == 0 {
+		return true // ボタンが1つ以下
+	}
+	first := h.at(i).frame
+	for key := uint16(1); key <= buttons; key <<= 1 {
+		if buttons&key == 0 {
+			continue
+		}
+		// そのボタンを最後に押したフレームを探す
+		found := false
+		for j := i; j < h.count; j++ {
+			e := h.at(j)
+			if e.pressed&key != 0 {
+				first = min(first, e.frame)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return int(h.at(i).frame-first) <= tolerance
+}
@@ -0,0 +1,152 @@
+package input
+
+import "testing"
+
+// taps キーを1フレームずつ押して離す入力（押しているキーのフレームごとの並び）
+func taps(keys ...uint16) []uint16 {
+	frames := make([]uint16, 0, len(keys)*2)
+	for _, k := range keys {
+		frames = append(frames, k, 0)
+	}
+	return frames
+}
+
+// hold keysをnフレーム押し続ける入力
+func hold(keys uint16, n int) []uint16 {
+	frames := make([]uint16, n)
+	for i := range frames {
+		frames[i] = keys
+	}
+	return frames
+}
+
+// join 入力をつなげる
+func join(parts ...[]uint16) []uint16 {
+	var frames []uint16
+	for _, p := range parts {
+		frames = append(frames, p...)
+	}
+	return frames
+}
+
+// runCombo 押しているキーをフレームごとにKeyStateとHistoryに通し、コマンドが成立したフレームを返す（-1は不成立）
+// KeyStateはKEYINPUTを読まずに、current/previousを直接書き換える
+func runCombo(c *Combo, frames []uint16) int {
+	var h History
+	ks := &KeyState{current: KeyAny, previous: KeyAny}
+	for i, held := range frames {
+		ks.previous = ks.current
+		ks.current = ^held & KeyAny
+		h.Update(ks)
+		if h.Match(c) {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestHistoryMatch(t *testing.T) {
+	konami := []uint16{KeyUp, KeyUp, KeyDown, KeyDown, KeyLeft, KeyRight, KeyLeft, KeyRight, KeyB, KeyA}
+	hadouken := []uint16{KeyDown, KeyDown | KeyRight, KeyRight, KeyA}
+
+	tests := []struct {
+		name   string
+		combo  Combo
+		frames []uint16
+		want   int // 成立するフレーム（-1は不成立）
+	}{
+		{
+			name:   "コナミコマンド",
+			combo:  Combo{Steps: konami},
+			frames: taps(konami...),
+			want:   18,
+		},
+		{
+			name:   "コナミコマンド（最後のAがない）",
+			combo:  Combo{Steps: konami},
+			frames: taps(konami[:9]...),
+			want:   -1,
+		},
+		{
+			name:   "コナミコマンド（順番違い）",
+			combo:  Combo{Steps: konami},
+			frames: taps(KeyUp, KeyDown, KeyUp, KeyDown, KeyLeft, KeyRight, KeyLeft, KeyRight, KeyB, KeyA),
+			want:   -1,
+		},
+		{
+			name:   "途中に別のキー（Strictでない）",
+			combo:  Combo{Steps: konami},
+			frames: taps(KeyUp, KeyUp, KeyDown, KeyDown, KeySelect, KeyLeft, KeyRight, KeyLeft, KeyRight, KeyB, KeyA),
+			want:   20,
+		},
+		{
+			name:   "途中に別のキー（Strict）",
+			combo:  Combo{Steps: konami, Strict: true},
+			frames: taps(KeyUp, KeyUp, KeyDown, KeyDown, KeySelect, KeyLeft, KeyRight, KeyLeft, KeyRight, KeyB, KeyA),
+			want:   -1,
+		},
+		{
+			name:   "Strictで別のキーがない",
+			combo:  Combo{Steps: konami, Strict: true},
+			frames: taps(konami...),
+			want:   18,
+		},
+		{
+			name:   "↓↘→+A",
+			combo:  Combo{Steps: hadouken, Window: 20},
+			frames: join(hold(KeyDown, 2), hold(KeyDown|KeyRight, 2), hold(KeyRight, 2), hold(KeyA, 1)),
+			want:   6,
+		},
+		{
+			name:   "↓↘→+A（Windowを超える）",
+			combo:  Combo{Steps: hadouken, Window: 20},
+			frames: join(hold(KeyDown, 2), hold(KeyDown|KeyRight, 2), hold(KeyRight, 20), hold(KeyA, 1)),
+			want:   -1,
+		},
+		{
+			name:   "↓↘→+A（斜めを飛ばす）",
+			combo:  Combo{Steps: hadouken, Window: 20},
+			frames: join(hold(KeyDown, 2), hold(KeyRight, 2), hold(KeyA, 1)),
+			want:   -1,
+		},
+		{
+			name:   "↓↘→+A（右を押しながらAでも成立）",
+			combo:  Combo{Steps: hadouken, Window: 20},
+			frames: join(hold(KeyDown, 2), hold(KeyDown|KeyRight, 2), hold(KeyRight, 2), hold(KeyRight|KeyA, 1)),
+			want:   6,
+		},
+		{
+			name:   "A+B同時押し（ずれがTolerance以内）",
+			combo:  Combo{Steps: []uint16{KeyA | KeyB}, Tolerance: 3},
+			frames: join(hold(KeyA, 3), hold(KeyA|KeyB, 1)),
+			want:   3,
+		},
+		{
+			name:   "A+B同時押し（Bが先）",
+			combo:  Combo{Steps: []uint16{KeyA | KeyB}, Tolerance: 3},
+			frames: join(hold(KeyB, 2), hold(KeyA|KeyB, 1)),
+			want:   2,
+		},
+		{
+			name:   "A+B同時押し（ずれがToleranceを超える）",
+			combo:  Combo{Steps: []uint16{KeyA | KeyB}, Tolerance: 3},
+			frames: join(hold(KeyA, 5), hold(KeyA|KeyB, 1)),
+			want:   -1,
+		},
+		{
+			name:   "↓の後にA+B同時押し",
+			combo:  Combo{Steps: []uint16{KeyDown, KeyA | KeyB}, Window: 10, Tolerance: 1},
+			frames: join(taps(KeyDown), hold(KeyA, 1), hold(KeyA|KeyB, 1)),
+			want:   3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := runCombo(&tt.combo, tt.frames)
+			if got != tt.want {
+				t.Errorf("matched at frame %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package input
+
+// 押している長さ・離している長さと、それを使った長押し・ダブルタップの判定
+// keysに複数のキーを指定した場合、フレーム数は最も長いキーの値、判定はどれか1つでも当てはまればtrue
+
+// HeldFrames 押し続けているフレーム数（押した瞬間が1、離していれば0）
+func (ks *KeyState) HeldFrames(keys uint16) int {
+	return maxOf(&ks.held, keys)
+}
+
+// ReleasedFrames 離してからのフレーム数（離した瞬間が1、押していれば0）
+func (ks *KeyState) ReleasedFrames(keys uint16) int {
+	n := 0
+	for i := 0; i < NumKeys; i++ {
+		if keys&(1<<i) != 0 && ks.held[i] == 0 {
+			n = max(n, int(ks.released[i]))
+		}
+	}
+	return n
+}
+
+// LastHeldFrames 最後に押していたフレーム数（離した瞬間に読むと溜め撃ちの長さになる）
+func (ks *KeyState) LastHeldFrames(keys uint16) int {
+	return maxOf(&ks.lastHeld, keys)
+}
+
+// IsLongPressed 押し続けてLongPressFramesに達した瞬間か
+func (ks *KeyState) IsLongPressed(keys uint16) bool {
+	for i := 0; i < NumKeys; i++ {
+		if keys&(1<<i) != 0 && int(ks.held[i]) == ks.LongPressFrames {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDoubleTapped 短く押して離し、DoubleTapFrames以内にもう一度押した瞬間か
+func (ks *KeyState) IsDoubleTapped(keys uint16) bool {
+	return ks.doubleTapped&keys != 0
+}
+
+// IsReleasedAfterHold LongPressFrames以上押し続けてから離した瞬間か
+func (ks *KeyState) IsReleasedAfterHold(keys uint16) bool {
+	for i := 0; i < NumKeys; i++ {
+		if keys&(1<<i) != 0 && ks.held[i] == 0 && ks.released[i] == 1 && int(ks.lastHeld[i]) >= ks.LongPressFrames {
+			return true
+		}
+	}
+	return false
+}
+
+// maxOf keysのキーのうち最も大きいフレーム数
+func maxOf(frames *[NumKeys]uint16, keys uint16) int {
+	n := 0
+	for i := 0; i < NumKeys; i++ {
+		if keys&(1<<i) != 0 {
+			n = max(n, int(frames[i]))
+		}
+	}
+	return n
+}
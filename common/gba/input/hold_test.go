@@ -0,0 +1,181 @@
+package input
+
+import "testing"
+
+// framesWhere 押しているキーをフレームごとに通し、condがtrueになったフレーム（最初のフレームが1）を返す
+func framesWhere(t *testing.T, frames []uint16, cond func(ks *KeyState) bool) []int {
+	set := fakeKeys(t)
+	ks := NewKeyState()
+	var got []int
+	for i, held := range frames {
+		set(held)
+		ks.Update()
+		if cond(ks) {
+			got = append(got, i+1)
+		}
+	}
+	return got
+}
+
+// equalFrames 2つのフレームの並びが等しいか
+func equalFrames(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestKeyStateHoldCounters(t *testing.T) {
+	set := fakeKeys(t)
+	ks := NewKeyState()
+
+	tests := []struct {
+		held     uint16
+		heldN    int // HeldFrames
+		released int // ReleasedFrames
+		lastHeld int // LastHeldFrames
+	}{
+		{KeyA, 1, 0, 0},
+		{KeyA, 2, 0, 0},
+		{KeyA, 3, 0, 0},
+		{0, 0, 1, 3},
+		{0, 0, 2, 3},
+		{KeyA, 1, 0, 3},
+		{0, 0, 1, 1},
+	}
+
+	for i, tt := range tests {
+		set(tt.held)
+		ks.Update()
+		if got := ks.HeldFrames(KeyA); got != tt.heldN {
+			t.Errorf("frame %d: HeldFrames = %d, want %d", i+1, got, tt.heldN)
+		}
+		if got := ks.ReleasedFrames(KeyA); got != tt.released {
+			t.Errorf("frame %d: ReleasedFrames = %d, want %d", i+1, got, tt.released)
+		}
+		if got := ks.LastHeldFrames(KeyA); got != tt.lastHeld {
+			t.Errorf("frame %d: LastHeldFrames = %d, want %d", i+1, got, tt.lastHeld)
+		}
+	}
+}
+
+func TestKeyStateHoldMultipleKeys(t *testing.T) {
+	set := fakeKeys(t)
+	ks := NewKeyState()
+	for _, held := range join(hold(KeyA, 2), hold(KeyA|KeyB, 3)) {
+		set(held)
+		ks.Update()
+	}
+	// 複数のキーは最も長いキーの値
+	if got := ks.HeldFrames(KeyA | KeyB); got != 5 {
+		t.Errorf("HeldFrames(A|B) = %d, want 5", got)
+	}
+	if got := ks.HeldFrames(KeyB); got != 3 {
+		t.Errorf("HeldFrames(B) = %d, want 3", got)
+	}
+}
+
+func TestKeyStateLongPress(t *testing.T) {
+	long := DefaultLongPressFrames
+
+	tests := []struct {
+		name   string
+		frames []uint16
+		cond   func(ks *KeyState) bool
+		want   []int
+	}{
+		{
+			name:   "長押しに達した瞬間だけ",
+			frames: hold(KeyA, long+10),
+			cond:   func(ks *KeyState) bool { return ks.IsLongPressed(KeyA) },
+			want:   []int{long},
+		},
+		{
+			name:   "長押しに届かない",
+			frames: join(hold(KeyA, long-1), hold(0, 5)),
+			cond:   func(ks *KeyState) bool { return ks.IsLongPressed(KeyA) },
+			want:   nil,
+		},
+		{
+			name:   "長押しの後に離した瞬間",
+			frames: join(hold(KeyA, long+5), hold(0, 5)),
+			cond:   func(ks *KeyState) bool { return ks.IsReleasedAfterHold(KeyA) },
+			want:   []int{long + 6},
+		},
+		{
+			name:   "短く押して離してもIsReleasedAfterHoldではない",
+			frames: join(hold(KeyA, 5), hold(0, 5)),
+			cond:   func(ks *KeyState) bool { return ks.IsReleasedAfterHold(KeyA) },
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := framesWhere(t, tt.frames, tt.cond)
+			if !equalFrames(got, tt.want) {
+				t.Errorf("true at frames %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeyStateDoubleTap(t *testing.T) {
+	doubleTapped := func(ks *KeyState) bool { return ks.IsDoubleTapped(KeyA) }
+
+	tests := []struct {
+		name   string
+		frames []uint16
+		want   []int
+	}{
+		{
+			name:   "2回目を押した瞬間",
+			frames: taps(KeyA, KeyA),
+			want:   []int{3},
+		},
+		{
+			name:   "3回目は2度目のダブルタップにならない",
+			frames: taps(KeyA, KeyA, KeyA),
+			want:   []int{3},
+		},
+		{
+			name:   "4回目で次のダブルタップ",
+			frames: taps(KeyA, KeyA, KeyA, KeyA),
+			want:   []int{3, 7},
+		},
+		{
+			name:   "押し直すのが遅い",
+			frames: join(hold(KeyA, 1), hold(0, DefaultDoubleTapFrames+1), hold(KeyA, 1)),
+			want:   nil,
+		},
+		{
+			name:   "猶予ちょうどで押し直す",
+			frames: join(hold(KeyA, 1), hold(0, DefaultDoubleTapFrames), hold(KeyA, 1)),
+			want:   []int{DefaultDoubleTapFrames + 2},
+		},
+		{
+			name:   "長押しの後に押し直す",
+			frames: join(hold(KeyA, DefaultLongPressFrames), hold(0, 1), hold(KeyA, 1)),
+			want:   nil,
+		},
+		{
+			name:   "別のキーはダブルタップにならない",
+			frames: taps(KeyB, KeyA),
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := framesWhere(t, tt.frames, doubleTapped)
+			if !equalFrames(got, tt.want) {
+				t.Errorf("double-tapped at frames %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -25,7 +25,8 @@ const (
 
 // キーの組み合わせ
 const (
-	KeyAny = 0x03FF // すべてのキー
+	KeyAny  = 0x03FF                               // すべてのキー
+	KeyDPad = KeyUp | KeyDown | KeyLeft | KeyRight // 方向キー
 )
 
 // IsKeyDown キーが押されているか（現在の状態）
@@ -52,3 +53,28 @@ func AreAllKeysDown(keys uint16) bool {
 func IsAnyKeyDown(keys uint16) bool {
 	return (KEYINPUT.Get() & keys) != keys
 }
+
+// キー割り込み（KEYCNT）
+const (
+	RegKEYCNT = 0x04000132
+
+	KeyIRQEnable = 1 << 14 // 割り込みを有効にする
+	KeyIRQAnd    = 1 << 15 // 指定したキーがすべて押されたとき（0ならいずれかが押されたとき）
+)
+
+var KEYCNT = (*volatile.Register16)(unsafe.Pointer(uintptr(RegKEYCNT)))
+
+// SetKeyInterrupt キー割り込みの条件を設定（interrupt.Keypadで処理を設定する）
+// all: trueならkeysがすべて押されたとき、falseならいずれかが押されたとき
+func SetKeyInterrupt(keys uint16, all bool) {
+	v := keys&KeyAny | KeyIRQEnable
+	if all {
+		v |= KeyIRQAnd
+	}
+	KEYCNT.Set(v)
+}
+
+// DisableKeyInterrupt キー割り込みを止める
+func DisableKeyInterrupt() {
+	KEYCNT.Set(0)
+}
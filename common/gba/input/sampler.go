@@ -0,0 +1,158 @@
+package input
+
+import (
+	"github.com/ryomak/gameboys/common/gba/display"
+	"github.com/ryomak/gameboys/common/gba/interrupt"
+	"github.com/ryomak/gameboys/common/gba/timer"
+)
+
+// 1フレームより細かい入力のタイミング
+// タイマーかHBlankの割り込みでKEYINPUTを1フレームに何度も読み、キーが変化したときの走査線を記録する
+// ゲージやリズムゲームで、押したのがフレームのどのあたりだったかを調べるのに使う
+
+const (
+	MaxSampledEdges = 16  // 記録するキーの変化の数
+	LinesPerFrame   = 228 // 1フレームのライン数（VBlankを含む）
+)
+
+// sampledEdge キーが変化した時刻
+type sampledEdge struct {
+	pressed  uint16
+	released uint16
+	stamp    uint32 // フレーム数*LinesPerFrame+走査線
+}
+
+// Sampler 1フレームに何度もキーを読むサンプラー
+type Sampler struct {
+	edges [MaxSampledEdges]sampledEdge
+	head  int
+	count int
+
+	last     uint16 // 最後に読んだKEYINPUT
+	frame    uint32 // 走査線が0に戻った回数
+	lastLine uint16
+	now      uint32 // Updateした時刻
+
+	every   int // HBlankで読む間隔（ライン、0は使っていない）
+	channel int // 使っているタイマー（-1は使っていない）
+}
+
+// NewSampler サンプラーを作成（StartTimerかStartHBlankで読み始める）
+func NewSampler() *Sampler {
+	return &Sampler{last: KEYINPUT.Get(), channel: -1}
+}
+
+// StartTimer タイマーの割り込みで1フレームにtimes回読む（channel: 0-3）
+func (s *Sampler) StartTimer(channel int, times int) {
+	s.Stop()
+	s.channel = channel & 3
+	interrupt.Set(interrupt.Timer0+interrupt.IRQ(s.channel), s.Sample)
+	timer.Start(s.channel, timer.PeriodFor(times, timer.Prescale64), timer.Prescale64|timer.IRQEnable)
+}
+
+// StartHBlank HBlankの割り込みでeveryラインごとに読む
+// 割り込みが1フレームに228回起きるため、タイマーより処理が重い
+func (s *Sampler) StartHBlank(every int) {
+	s.Stop()
+	s.every = max(every, 1)
+	interrupt.Set(interrupt.HBlank, s.sampleHBlank)
+}
+
+// Stop 読むのをやめる
+func (s *Sampler) Stop() {
+	if s.channel >= 0 {
+		timer.Stop(s.channel)
+		interrupt.Disable(interrupt.Timer0 + interrupt.IRQ(s.channel))
+		s.channel = -1
+	}
+	if s.every > 0 {
+		interrupt.Disable(interrupt.HBlank)
+		s.every = 0
+	}
+}
+
+// sampleHBlank everyラインごとに読む（HBlank割り込みで呼ばれる）
+func (s *Sampler) sampleHBlank() {
+	if int(display.VCOUNT.Get())%s.every == 0 {
+		s.Sample()
+	}
+}
+
+// Sample KEYINPUTを読み、変化があれば時刻を記録（割り込みから呼ばれる。直接呼んでもよい）
+func (s *Sampler) Sample() {
+	line := display.VCOUNT.Get()
+	if line < s.lastLine {
+		s.frame++
+	}
+	s.lastLine = line
+
+	keys := KEYINPUT.Get()
+	changed := (keys ^ s.last) & KeyAny
+	if changed == 0 {
+		return
+	}
+	s.edges[s.head] = sampledEdge{
+		pressed:  changed & s.last, // 1（離している）から0（押下）になったキー
+		released: changed & keys,
+		stamp:    s.stamp(line),
+	}
+	s.head = (s.head + 1) % MaxSampledEdges
+	if s.count < MaxSampledEdges {
+		s.count++
+	}
+	s.last = keys
+}
+
+// stamp フレーム数と走査線からの時刻
+func (s *Sampler) stamp(line uint16) uint32 {
+	return s.frame*LinesPerFrame + uint32(line)
+}
+
+// Update このフレームの時刻を記録（KeyState.Updateと同じタイミングで毎フレーム呼ぶ）
+func (s *Sampler) Update() {
+	state := interrupt.DisableAll()
+	s.Sample()
+	s.now = s.stamp(s.lastLine)
+	interrupt.Restore(state)
+}
+
+// LinesSincePress keysのいずれかが最後に押されてから、Updateまでのライン数
+// 押された記録がなければfalse。LinesPerFrameで割るとフレーム単位になる
+func (s *Sampler) LinesSincePress(keys uint16) (int, bool) {
+	return s.linesSince(keys, true)
+}
+
+// LinesSinceRelease keysのいずれかが最後に離されてから、Updateまでのライン数
+func (s *Sampler) LinesSinceRelease(keys uint16) (int, bool) {
+	return s.linesSince(keys, false)
+}
+
+// PressLine keysのいずれかが最後に押された走査線（0-227）
+func (s *Sampler) PressLine(keys uint16) (int, bool) {
+	lines, ok := s.LinesSincePress(keys)
+	if !ok {
+		return 0, false
+	}
+	line := (int(s.now%LinesPerFrame) - lines%LinesPerFrame + LinesPerFrame) % LinesPerFrame
+	return line, true
+}
+
+// linesSince 最後に押された（離された）変化を新しい方から探す
+func (s *Sampler) linesSince(keys uint16, pressed bool) (int, bool) {
+	state := interrupt.DisableAll()
+	defer interrupt.Restore(state)
+	for i := 0; i < s.count; i++ {
+		e := &s.edges[(s.head-1-i+MaxSampledEdges)%MaxSampledEdges]
+		if e.stamp > s.now {
+			continue // Updateの後の変化はまだ見せない
+		}
+		bits := e.released
+		if pressed {
+			bits = e.pressed
+		}
+		if bits&keys != 0 {
+			return int(s.now - e.stamp), true
+		}
+	}
+	return 0, false
+}
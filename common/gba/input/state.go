@@ -1,23 +1,128 @@
 package input
 
+// NumKeys キーの数（KeyA-KeyLのビット）
+const NumKeys = 10
+
+// キーリピートの初期値（フレーム）
+const (
+	DefaultRepeatDelay    = 20 // 押してから最初のリピートまで
+	DefaultRepeatInterval = 4  // リピートの間隔
+)
+
+// 長押し・ダブルタップの初期値（フレーム）
+const (
+	DefaultLongPressFrames = 30 // 長押しになるまで
+	DefaultDoubleTapFrames = 15 // 離してから次に押すまでの猶予
+)
+
+// maxFrames フレーム数の上限（これ以上は数えない）
+const maxFrames = 0xFFFF
+
 // KeyState キー入力状態管理
 type KeyState struct {
+	LongPressFrames int // 長押しになるまでのフレーム数
+	DoubleTapFrames int // ダブルタップとみなす、離してから次に押すまでのフレーム数
+
 	current  uint16
 	previous uint16
+
+	held           [NumKeys]uint16 // 押し続けているフレーム数（離していれば0）
+	released       [NumKeys]uint16 // 離してからのフレーム数（押していれば直前に離していた長さ）
+	lastHeld       [NumKeys]uint16 // 最後に押していた長さ
+	repeatDelay    [NumKeys]uint16
+	repeatInterval [NumKeys]uint16
+
+	doubleTapped uint16 // このフレームでダブルタップになったキー
+	tapChain     uint16 // ダブルタップの2回目を押しているキー（3回目を2度目のダブルタップにしない）
 }
 
 // NewKeyState 入力状態管理を初期化
 func NewKeyState() *KeyState {
-	return &KeyState{
-		current:  KEYINPUT.Get(),
-		previous: KEYINPUT.Get(),
+	ks := &KeyState{
+		LongPressFrames: DefaultLongPressFrames,
+		DoubleTapFrames: DefaultDoubleTapFrames,
+		current:         KEYINPUT.Get(),
+		previous:        KEYINPUT.Get(),
+	}
+	for i := range ks.released {
+		ks.released[i] = maxFrames
 	}
+	ks.SetRepeat(KeyAny, DefaultRepeatDelay, DefaultRepeatInterval)
+	return ks
 }
 
 // Update 入力状態を更新（毎フレーム呼び出す）
 func (ks *KeyState) Update() {
 	ks.previous = ks.current
 	ks.current = KEYINPUT.Get()
+	ks.doubleTapped = 0
+
+	for i := 0; i < NumKeys; i++ {
+		bit := uint16(1) << i
+		if ks.current&bit != 0 {
+			if ks.held[i] > 0 {
+				ks.lastHeld[i] = ks.held[i]
+				ks.held[i] = 0
+				ks.released[i] = 0
+			}
+			if ks.released[i] < maxFrames {
+				ks.released[i]++
+			}
+			continue
+		}
+
+		if ks.held[i] < maxFrames {
+			ks.held[i]++
+		}
+		if ks.held[i] == 1 {
+			ks.updateDoubleTap(i, bit)
+		}
+	}
+}
+
+// updateDoubleTap 押された瞬間に、短く押して離してからすぐ押し直したかを調べる
+func (ks *KeyState) updateDoubleTap(i int, bit uint16) {
+	quick := int(ks.released[i]) <= ks.DoubleTapFrames && int(ks.lastHeld[i]) < ks.LongPressFrames
+	if quick && ks.tapChain&bit == 0 {
+		ks.doubleTapped |= bit
+		ks.tapChain |= bit
+		return
+	}
+	ks.tapChain &^= bit
+}
+
+// SetRepeat キーリピートの設定（keysは複数のキーをまとめて指定できる）
+// delay: 押してから最初のリピートまでのフレーム数（押したフレームを1と数える）
+// interval: その後のリピートの間隔（0でリピートしない）。どちらも0-0xFFFFに収める
+func (ks *KeyState) SetRepeat(keys uint16, delay, interval int) {
+	delay = min(max(delay, 0), maxFrames)
+	interval = min(max(interval, 0), maxFrames)
+	for i := 0; i < NumKeys; i++ {
+		if keys&(1<<i) != 0 {
+			ks.repeatDelay[i] = uint16(delay)
+			ks.repeatInterval[i] = uint16(interval)
+		}
+	}
+}
+
+// IsRepeated 押された瞬間と、押し続けている間のリピートのフレームか（メニューや数値の入力に使う）
+// keysに複数のキーを指定した場合は、どれか1つでもリピートのフレームならtrue
+func (ks *KeyState) IsRepeated(keys uint16) bool {
+	for i := 0; i < NumKeys; i++ {
+		if keys&(1<<i) == 0 {
+			continue
+		}
+		held := int(ks.held[i])
+		if held == 1 && ks.previous&(1<<i) != 0 {
+			return true
+		}
+		delay := int(ks.repeatDelay[i])
+		interval := int(ks.repeatInterval[i])
+		if interval > 0 && held > 0 && held >= delay && (held-delay)%interval == 0 {
+			return true
+		}
+	}
+	return false
 }
 
 // IsPressed キーが押された瞬間（トリガー）
@@ -0,0 +1,122 @@
+package input
+
+import (
+	"runtime/volatile"
+	"testing"
+)
+
+// fakeKeys KEYINPUTをメモリ上のレジスタに置き換え、押しているキーを設定する関数を返す
+func fakeKeys(t *testing.T) func(held uint16) {
+	orig := KEYINPUT
+	reg := &volatile.Register16{}
+	reg.Set(KeyAny)
+	KEYINPUT = reg
+	t.Cleanup(func() { KEYINPUT = orig })
+	return func(held uint16) {
+		reg.Set(^held & KeyAny)
+	}
+}
+
+// repeatFrames 押しているキーをフレームごとに通し、IsRepeated(key)がtrueになったフレーム（押したフレームが1）を返す
+func repeatFrames(t *testing.T, key uint16, delay, interval int, frames []uint16) []int {
+	set := fakeKeys(t)
+	ks := NewKeyState()
+	ks.SetRepeat(key, delay, interval)
+
+	var got []int
+	for i, held := range frames {
+		set(held)
+		ks.Update()
+		if ks.IsRepeated(key) {
+			got = append(got, i+1)
+		}
+	}
+	return got
+}
+
+func TestKeyStateRepeat(t *testing.T) {
+	tests := []struct {
+		name     string
+		delay    int
+		interval int
+		frames   []uint16
+		want     []int
+	}{
+		{
+			name:   "押した瞬間",
+			delay:  20,
+			frames: hold(KeyA, 1),
+			want:   []int{1},
+		},
+		{
+			name:     "最初のリピートはdelayのフレーム",
+			delay:    20,
+			interval: 4,
+			frames:   hold(KeyA, 28),
+			want:     []int{1, 20, 24, 28},
+		},
+		{
+			name:     "intervalが0ならリピートしない",
+			delay:    5,
+			interval: 0,
+			frames:   hold(KeyA, 20),
+			want:     []int{1},
+		},
+		{
+			name:     "離すと数え直す",
+			delay:    3,
+			interval: 2,
+			frames:   join(hold(KeyA, 5), hold(0, 1), hold(KeyA, 3)),
+			want:     []int{1, 3, 5, 7, 9},
+		},
+		{
+			name:     "255を超えるdelay",
+			delay:    300,
+			interval: 10,
+			frames:   hold(KeyA, 310),
+			want:     []int{1, 300, 310},
+		},
+		{
+			name:     "負の値は0",
+			delay:    -5,
+			interval: -1,
+			frames:   hold(KeyA, 5),
+			want:     []int{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := repeatFrames(t, KeyA, tt.delay, tt.interval, tt.frames)
+			if len(got) != len(tt.want) {
+				t.Fatalf("repeated at %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("repeated at %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestKeyStateRepeatPerKey(t *testing.T) {
+	set := fakeKeys(t)
+	ks := NewKeyState()
+	ks.SetRepeat(KeyUp, 2, 1)
+	ks.SetRepeat(KeyDown, 0, 0)
+
+	set(KeyUp | KeyDown)
+	ks.Update()
+	set(KeyUp | KeyDown)
+	ks.Update()
+	if !ks.IsRepeated(KeyUp) {
+		t.Error("KeyUp should repeat on frame 2")
+	}
+	if ks.IsRepeated(KeyDown) {
+		t.Error("KeyDown should not repeat")
+	}
+	if !ks.IsRepeated(KeyUp | KeyDown) {
+		t.Error("IsRepeated should be true if any of the keys repeats")
+	}
+}
@@ -0,0 +1,182 @@
+package interrupt
+
+import (
+	"runtime/interrupt"
+	"runtime/volatile"
+	"unsafe"
+
+	"github.com/ryomak/gameboys/common/gba/display"
+)
+
+// 割り込み（IRQ）の設定
+// TinyGoのruntime/interruptに登録したハンドラから、IRQごとに設定した関数を呼び出す
+// runtime/interruptへの登録は定数の番号で行う必要があるため、IRQごとに登録を分けている
+
+// レジスタアドレス
+const (
+	RegIE  = 0x04000200 // 割り込みの有効化
+	RegIF  = 0x04000202 // 割り込みの要求（1を書くと解除）
+	RegIME = 0x04000208 // 割り込み全体の有効化
+
+	// RegBIOSIF BIOSのIntrWait・VBlankIntrWaitが待つ割り込みのフラグ（処理した割り込みのビットを立てる）
+	RegBIOSIF = 0x03007FF8
+)
+
+// DISPSTATの割り込みのビット
+const (
+	dispstatVBlankIRQ = 1 << 3
+	dispstatHBlankIRQ = 1 << 4
+	dispstatVCountIRQ = 1 << 5
+	dispstatVCount    = 0xFF << 8 // VCount割り込みを起こすライン
+)
+
+// IRQ 割り込みの種類（IE/IFのビット番号）
+type IRQ uint8
+
+const (
+	VBlank IRQ = iota
+	HBlank
+	VCount
+	Timer0
+	Timer1
+	Timer2
+	Timer3
+	Serial
+	DMA0
+	DMA1
+	DMA2
+	DMA3
+	Keypad
+	GamePak
+	MaxIRQ
+)
+
+// レジスタアクセス用の変数
+var (
+	IE  = (*volatile.Register16)(unsafe.Pointer(uintptr(RegIE)))
+	IF  = (*volatile.Register16)(unsafe.Pointer(uintptr(RegIF)))
+	IME = (*volatile.Register16)(unsafe.Pointer(uintptr(RegIME)))
+
+	BIOSIF = (*volatile.Register16)(unsafe.Pointer(uintptr(RegBIOSIF)))
+)
+
+// handlers IRQごとに呼び出す関数
+var handlers [MaxIRQ]func()
+
+// Set IRQの処理を設定して有効にする（nilで無効にする）
+// VBlank・HBlank・VCountはDISPSTATの割り込みも有効にする
+// タイマー・DMA・キー入力は、それぞれの制御レジスタでも割り込みを有効にする必要がある
+func Set(irq IRQ, handler func()) {
+	if irq >= MaxIRQ {
+		return
+	}
+	if handler == nil {
+		Disable(irq)
+		return
+	}
+	handlers[irq] = handler
+	register(irq)
+	display.DISPSTAT.SetBits(dispstatBit(irq))
+	IME.Set(1)
+}
+
+// Disable IRQを無効にする
+func Disable(irq IRQ) {
+	if irq >= MaxIRQ {
+		return
+	}
+	IE.ClearBits(1 << irq)
+	display.DISPSTAT.ClearBits(dispstatBit(irq))
+	handlers[irq] = nil
+}
+
+// SetVCountLine VCount割り込みを起こすラインを設定（0-227、それ以外は起こらない）
+func SetVCountLine(line int) {
+	if line < 0 || line > 0xFF {
+		line = 0xFF
+	}
+	v := display.DISPSTAT.Get()
+	display.DISPSTAT.Set(v&^dispstatVCount | uint16(line)<<8)
+}
+
+// DisableAll すべての割り込みを止め、元に戻すための状態を返す（割り込みと共有する値を書き換える間に使う）
+func DisableAll() interrupt.State {
+	return interrupt.Disable()
+}
+
+// Restore DisableAllで止めた割り込みを元に戻す
+func Restore(state interrupt.State) {
+	interrupt.Restore(state)
+}
+
+// dispstatBit IRQに対応するDISPSTATの割り込みのビット
+func dispstatBit(irq IRQ) uint16 {
+	switch irq {
+	case VBlank:
+		return dispstatVBlankIRQ
+	case HBlank:
+		return dispstatHBlankIRQ
+	case VCount:
+		return dispstatVCountIRQ
+	}
+	return 0
+}
+
+// register runtime/interruptにハンドラを登録してIEのビットを立てる
+func register(irq IRQ) {
+	switch irq {
+	case VBlank:
+		interrupt.New(0, handleVBlank).Enable()
+	case HBlank:
+		interrupt.New(1, handleHBlank).Enable()
+	case VCount:
+		interrupt.New(2, handleVCount).Enable()
+	case Timer0:
+		interrupt.New(3, handleTimer0).Enable()
+	case Timer1:
+		interrupt.New(4, handleTimer1).Enable()
+	case Timer2:
+		interrupt.New(5, handleTimer2).Enable()
+	case Timer3:
+		interrupt.New(6, handleTimer3).Enable()
+	case Serial:
+		interrupt.New(7, handleSerial).Enable()
+	case DMA0:
+		interrupt.New(8, handleDMA0).Enable()
+	case DMA1:
+		interrupt.New(9, handleDMA1).Enable()
+	case DMA2:
+		interrupt.New(10, handleDMA2).Enable()
+	case DMA3:
+		interrupt.New(11, handleDMA3).Enable()
+	case Keypad:
+		interrupt.New(12, handleKeypad).Enable()
+	case GamePak:
+		interrupt.New(13, handleGamePak).Enable()
+	}
+	IE.SetBits(1 << irq)
+}
+
+// call 設定された関数を呼び、BIOSのIntrWaitに割り込みを処理したことを伝える
+func call(irq IRQ) {
+	if h := handlers[irq]; h != nil {
+		h()
+	}
+	BIOSIF.SetBits(1 << irq)
+}
+
+// runtime/interruptに登録するハンドラ（クロージャは使えないため、IRQごとに用意する）
+func handleVBlank(interrupt.Interrupt)  { call(VBlank) }
+func handleHBlank(interrupt.Interrupt)  { call(HBlank) }
+func handleVCount(interrupt.Interrupt)  { call(VCount) }
+func handleTimer0(interrupt.Interrupt)  { call(Timer0) }
+func handleTimer1(interrupt.Interrupt)  { call(Timer1) }
+func handleTimer2(interrupt.Interrupt)  { call(Timer2) }
+func handleTimer3(interrupt.Interrupt)  { call(Timer3) }
+func handleSerial(interrupt.Interrupt)  { call(Serial) }
+func handleDMA0(interrupt.Interrupt)    { call(DMA0) }
+func handleDMA1(interrupt.Interrupt)    { call(DMA1) }
+func handleDMA2(interrupt.Interrupt)    { call(DMA2) }
+func handleDMA3(interrupt.Interrupt)    { call(DMA3) }
+func handleKeypad(interrupt.Interrupt)  { call(Keypad) }
+func handleGamePak(interrupt.Interrupt) { call(GamePak) }
@@ -100,3 +100,32 @@ func WaitDMA3() {
 		// DMA転送中は待機
 	}
 }
+
+// dmaBase DMAチャンネル（0-3）のレジスタの先頭アドレス
+func dmaBase(channel int) uintptr {
+	return uintptr(RegDMA0SAD + channel*12)
+}
+
+// DMAStart 指定チャンネルで転送を開始（HBlank・VBlankごとの繰り返し転送にも使う）
+// count: 転送する単位数、mode: DMAStartHBlank|DMARepeat などの組み合わせ
+func DMAStart(channel int, dst, src unsafe.Pointer, count uint32, mode uint16) {
+	if channel < 0 || channel > 3 {
+		return
+	}
+	base := dmaBase(channel)
+	cntReg := (*volatile.Register32)(unsafe.Pointer(base + 8))
+
+	// 動作中の転送を止めてから設定し直す
+	cntReg.Set(0)
+	(*volatile.Register32)(unsafe.Pointer(base)).Set(uint32(uintptr(src)))
+	(*volatile.Register32)(unsafe.Pointer(base + 4)).Set(uint32(uintptr(dst)))
+	cntReg.Set((uint32(mode|DMAEnable) << 16) | (count & 0xFFFF))
+}
+
+// DMAStop 指定チャンネルの転送を止める（繰り返し転送の停止に使う）
+func DMAStop(channel int) {
+	if channel < 0 || channel > 3 {
+		return
+	}
+	(*volatile.Register16)(unsafe.Pointer(dmaBase(channel) + 10)).Set(0)
+}
@@ -0,0 +1,46 @@
+package memory
+
+import (
+	"runtime/volatile"
+	"unsafe"
+)
+
+// カートリッジのSRAM（セーブデータ）
+// SRAMは8bitバスのため、1バイトずつ読み書きする
+
+// SRAMのアドレスと大きさ
+const (
+	RegSRAM  = 0x0E000000
+	SRAMSize = 0x8000 // 32KB
+)
+
+// sramSignature エミュレータ・書き込みツールがセーブの種類をSRAMと判定するための文字列
+// ROMに残すため、SRAMWriteから参照する
+var sramSignature = [...]byte{'S', 'R', 'A', 'M', '_', 'V', '1', '1', '3', 0, 0, 0}
+
+// SRAMRead offsetからdstの長さだけ読み込み、読み込んだバイト数を返す
+func SRAMRead(offset int, dst []byte) int {
+	n := sramLength(offset, len(dst))
+	for i := 0; i < n; i++ {
+		dst[i] = volatile.LoadUint8((*uint8)(unsafe.Pointer(uintptr(RegSRAM + offset + i))))
+	}
+	return n
+}
+
+// SRAMWrite offsetからsrcを書き込み、書き込んだバイト数を返す
+func SRAMWrite(offset int, src []byte) int {
+	volatile.LoadUint8(&sramSignature[0])
+	n := sramLength(offset, len(src))
+	for i := 0; i < n; i++ {
+		volatile.StoreUint8((*uint8)(unsafe.Pointer(uintptr(RegSRAM+offset+i))), src[i])
+	}
+	return n
+}
+
+// sramLength SRAMの範囲に収まるバイト数
+func sramLength(offset, length int) int {
+	if offset < 0 || offset >= SRAMSize {
+		return 0
+	}
+	return min(length, SRAMSize-offset)
+}
@@ -0,0 +1,250 @@
+package palette
+
+import (
+	"github.com/ryomak/gameboys/common/gba/graphics"
+	"github.com/ryomak/gameboys/common/math"
+)
+
+// MaxEffects Animatorで同時に実行できるエフェクト数
+const MaxEffects = 8
+
+// Effect 毎フレーム実行するパレットエフェクト
+// 元の色（base）を読み、シャドウコピーだけを書き換える
+type Effect interface {
+	// Update 1フレーム分進める。終了したらtrueを返す
+	Update(m *Manager) bool
+}
+
+// lerpColor 15bitカラーをnum/denの割合で補間
+func lerpColor(a, b uint16, num, den int) uint16 {
+	if den <= 0 || num >= den {
+		return b
+	}
+	return graphics.LerpRGB15(a, b, math.NewFixed(int32(num)).Div(math.NewFixed(int32(den))))
+}
+
+// Fade 範囲の色を指定色へ（または指定色から）フェードする
+type Fade struct {
+	Range  Range
+	Color  uint16 // フェード先（FadeInではフェード元）の色
+	Frames int    // 所要フレーム数
+	In     bool   // trueならColorから元の色へ戻す
+	frame  int
+}
+
+// NewFadeOut 元の色から指定色へフェード（黒ならフェードアウト、白ならホワイトアウト）
+func NewFadeOut(r Range, color uint16, frames int) Fade {
+	return Fade{Range: r, Color: color, Frames: frames}
+}
+
+// NewFadeIn 指定色から元の色へフェード
+func NewFadeIn(r Range, color uint16, frames int) Fade {
+	return Fade{Range: r, Color: color, Frames: frames, In: true}
+}
+
+// Reset フェードを最初からやり直す
+func (f *Fade) Reset() {
+	f.frame = 0
+}
+
+// Update 1フレーム分進める（範囲がパレットに収まっていなければ何もせずに終わる）
+func (f *Fade) Update(m *Manager) bool {
+	if !f.Range.Valid() {
+		return true
+	}
+	if f.Frames <= 0 {
+		// 0フレームなら終わりの状態にする（FadeInは元の色、FadeOutはColor）
+		for i := f.Range.Start; i < f.Range.End(); i++ {
+			c := f.Color
+			if f.In {
+				c = m.base[i]
+			}
+			m.SetWorking(uint8(i), c)
+		}
+		return true
+	}
+	if f.frame < f.Frames {
+		f.frame++
+	}
+	num := f.frame
+	if f.In {
+		num = f.Frames - f.frame
+	}
+	for i := f.Range.Start; i < f.Range.End(); i++ {
+		m.SetWorking(uint8(i), lerpColor(m.base[i], f.Color, num, f.Frames))
+	}
+	return f.frame >= f.Frames
+}
+
+// CrossFade 範囲の色を別のパレットへフェードする
+// 終了時に元の色も移行先のパレットに置き換わる
+type CrossFade struct {
+	Range  Range
+	Target []uint16 // 移行先のパレット（Range.Count色）
+	Frames int
+	frame  int
+}
+
+// NewCrossFade 2つのパレット間のフェードを作成
+func NewCrossFade(r Range, target []uint16, frames int) CrossFade {
+	return CrossFade{Range: r, Target: target, Frames: frames}
+}
+
+// Update 1フレーム分進める（範囲がパレットに収まっていなければ何もせずに終わる）
+func (c *CrossFade) Update(m *Manager) bool {
+	if !c.Range.Valid() {
+		return true
+	}
+	if c.frame < c.Frames {
+		c.frame++
+	}
+	for i := 0; i < c.Range.Count && i < len(c.Target); i++ {
+		index := c.Range.Start + i
+		m.SetWorking(uint8(index), lerpColor(m.base[index], c.Target[i], c.frame, c.Frames))
+	}
+	if c.frame >= c.Frames {
+		m.LoadRange(c.Range, c.Target)
+		return true
+	}
+	return false
+}
+
+// Flash 1色を一定間隔で点滅させる
+type Flash struct {
+	Index  uint8
+	Color  uint16 // 点灯時の色
+	Period int    // 点灯・消灯それぞれのフレーム数
+	Frames int    // 全体のフレーム数（0なら無限）
+	frame  int
+}
+
+// NewFlash 点滅を作成
+func NewFlash(index uint8, color uint16, period, frames int) Flash {
+	return Flash{Index: index, Color: color, Period: period, Frames: frames}
+}
+
+// Update 1フレーム分進める（終了時は元の色に戻す）
+func (f *Flash) Update(m *Manager) bool {
+	f.frame++
+	if f.Frames > 0 && f.frame >= f.Frames {
+		m.SetWorking(f.Index, m.base[f.Index])
+		return true
+	}
+	period := f.Period
+	if period <= 0 {
+		period = 1
+	}
+	if (f.frame/period)%2 == 0 {
+		m.SetWorking(f.Index, f.Color)
+	} else {
+		m.SetWorking(f.Index, m.base[f.Index])
+	}
+	return false
+}
+
+// Cycle 範囲の色をローテーションする（水面や電飾の表現）
+type Cycle struct {
+	Range   Range
+	Period  int  // 1つずらすまでのフレーム数
+	Frames  int  // 全体のフレーム数（0なら無限）
+	Reverse bool // trueなら逆方向に回す
+	frame   int
+}
+
+// NewCycle カラーサイクルを作成
+func NewCycle(r Range, period, frames int) Cycle {
+	return Cycle{Range: r, Period: period, Frames: frames}
+}
+
+// Update 1フレーム分進める（範囲がパレットに収まっていなければ何もせずに終わる）
+func (c *Cycle) Update(m *Manager) bool {
+	if !c.Range.Valid() {
+		return true
+	}
+	c.frame++
+	period := c.Period
+	if period <= 0 {
+		period = 1
+	}
+	if c.frame%period == 0 && c.Range.Count > 1 {
+		rotate(m.base[c.Range.Start:c.Range.End()], c.Reverse)
+		rotate(m.shadow[c.Range.Start:c.Range.End()], c.Reverse)
+		m.dirty = true
+	}
+	return c.Frames > 0 && c.frame >= c.Frames
+}
+
+// rotate スライスを1つずらす
+func rotate(colors []uint16, reverse bool) {
+	last := len(colors) - 1
+	if reverse {
+		first := colors[0]
+		copy(colors, colors[1:])
+		colors[last] = first
+	} else {
+		end := colors[last]
+		copy(colors[1:], colors[:last])
+		colors[0] = end
+	}
+}
+
+// Animator 複数のエフェクトをまとめて実行する
+type Animator struct {
+	effects [MaxEffects]Effect
+}
+
+// Add エフェクトを追加（空きがなければfalse）
+func (a *Animator) Add(e Effect) bool {
+	for i := range a.effects {
+		if a.effects[i] == e {
+			return true
+		}
+	}
+	for i := range a.effects {
+		if a.effects[i] == nil {
+			a.effects[i] = e
+			return true
+		}
+	}
+	return false
+}
+
+// Remove エフェクトを途中で取り除く（色は元に戻さない）
+func (a *Animator) Remove(e Effect) {
+	for i := range a.effects {
+		if a.effects[i] == e {
+			a.effects[i] = nil
+		}
+	}
+}
+
+// IsActive エフェクトが実行中か
+func (a *Animator) IsActive(e Effect) bool {
+	for i := range a.effects {
+		if a.effects[i] == e {
+			return true
+		}
+	}
+	return false
+}
+
+// Count 実行中のエフェクト数
+func (a *Animator) Count() int {
+	n := 0
+	for i := range a.effects {
+		if a.effects[i] != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// Update 全エフェクトを1フレーム分進める（毎フレーム呼び出す）
+// 終了したエフェクトは自動的に取り除かれる
+func (a *Animator) Update(m *Manager) {
+	for i := range a.effects {
+		if a.effects[i] != nil && a.effects[i].Update(m) {
+			a.effects[i] = nil
+		}
+	}
+}
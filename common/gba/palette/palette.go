@@ -0,0 +1,291 @@
+package palette
+
+import (
+	"unsafe"
+
+	"github.com/ryomak/gameboys/common/gba/graphics"
+	"github.com/ryomak/gameboys/common/gba/memory"
+)
+
+// パレットRAMアドレス
+const (
+	RegPaletteBG  = 0x05000000 // 背景用パレット（256色）
+	RegPaletteOBJ = 0x05000200 // スプライト用パレット（256色）
+)
+
+const (
+	Size     = 256 // 1パレットの色数
+	MaxNamed = 16  // 名前付き範囲の最大数
+)
+
+// Range パレットの連続したインデックス範囲
+type Range struct {
+	Start int // 先頭インデックス
+	Count int // 色数
+}
+
+// Index 範囲内のi番目のパレットインデックスを取得
+func (r Range) Index(i int) uint8 {
+	return uint8(r.Start + i)
+}
+
+// End 範囲の終端（最後のインデックス+1）
+func (r Range) End() int {
+	return r.Start + r.Count
+}
+
+// Valid 範囲が1色以上で、パレットの中に収まっているか
+func (r Range) Valid() bool {
+	return r.Start >= 0 && r.Count > 0 && r.End() <= Size
+}
+
+// namedRange 名前付きで確保された範囲
+type namedRange struct {
+	name string
+	r    Range
+}
+
+// Manager パレット管理
+// RAM上のシャドウコピーを編集し、VBlank中にCommitでパレットRAMへ転送する
+// baseは読み込んだ元の色、shadowはエフェクト適用後の実際に転送される色
+type Manager struct {
+	addr   uintptr           // 転送先のパレットRAMアドレス
+	base   [Size]uint16      // 元の色
+	shadow [Size]uint16      // シャドウコピー（転送される色）
+	used   [Size / 32]uint32 // 確保済みインデックスのビットマップ
+	names  [MaxNamed]namedRange
+	dirty  bool // 未転送の変更があるか
+}
+
+// NewManager パレット管理を初期化
+// addr: RegPaletteBG または RegPaletteOBJ
+func NewManager(addr uintptr) *Manager {
+	return &Manager{
+		addr:  addr,
+		dirty: true,
+	}
+}
+
+// isUsed インデックスが確保済みか
+func (m *Manager) isUsed(index int) bool {
+	return m.used[index>>5]&(1<<uint(index&31)) != 0
+}
+
+// mark インデックス範囲の確保状態を設定
+func (m *Manager) mark(r Range, used bool) {
+	for i := r.Start; i < r.End(); i++ {
+		if used {
+			m.used[i>>5] |= 1 << uint(i&31)
+		} else {
+			m.used[i>>5] &^= 1 << uint(i&31)
+		}
+	}
+}
+
+// Reserve 指定位置の範囲を確保（既に使われていれば失敗）
+func (m *Manager) Reserve(start, count int) (Range, bool) {
+	if start < 0 || count <= 0 || start+count > Size {
+		return Range{}, false
+	}
+	r := Range{Start: start, Count: count}
+	for i := r.Start; i < r.End(); i++ {
+		if m.isUsed(i) {
+			return Range{}, false
+		}
+	}
+	m.mark(r, true)
+	return r, true
+}
+
+// Alloc 空いている範囲を先頭から探して確保
+func (m *Manager) Alloc(count int) (Range, bool) {
+	if count <= 0 || count > Size {
+		return Range{}, false
+	}
+	run := 0
+	for i := 0; i < Size; i++ {
+		if m.isUsed(i) {
+			run = 0
+			continue
+		}
+		run++
+		if run == count {
+			r := Range{Start: i - count + 1, Count: count}
+			m.mark(r, true)
+			return r, true
+		}
+	}
+	return Range{}, false
+}
+
+// Free 範囲を解放（名前付きの場合は名前も解除）
+func (m *Manager) Free(r Range) {
+	if !r.Valid() {
+		return
+	}
+	m.mark(r, false)
+	for i := range m.names {
+		if m.names[i].name != "" && m.names[i].r == r {
+			m.names[i] = namedRange{}
+		}
+	}
+}
+
+// ReserveNamed 指定位置の範囲を名前付きで確保
+// 同じ名前が既にあればその範囲を返す
+func (m *Manager) ReserveNamed(name string, start, count int) (Range, bool) {
+	if r, ok := m.Lookup(name); ok {
+		return r, true
+	}
+	slot := m.freeNameSlot()
+	if slot < 0 {
+		return Range{}, false
+	}
+	r, ok := m.Reserve(start, count)
+	if !ok {
+		return Range{}, false
+	}
+	m.names[slot] = namedRange{name: name, r: r}
+	return r, true
+}
+
+// AllocNamed 空いている範囲を名前付きで確保
+// 同じ名前が既にあればその範囲を返す
+func (m *Manager) AllocNamed(name string, count int) (Range, bool) {
+	if r, ok := m.Lookup(name); ok {
+		return r, true
+	}
+	slot := m.freeNameSlot()
+	if slot < 0 {
+		return Range{}, false
+	}
+	r, ok := m.Alloc(count)
+	if !ok {
+		return Range{}, false
+	}
+	m.names[slot] = namedRange{name: name, r: r}
+	return r, true
+}
+
+// Lookup 名前から範囲を取得
+func (m *Manager) Lookup(name string) (Range, bool) {
+	if name == "" {
+		return Range{}, false
+	}
+	for i := range m.names {
+		if m.names[i].name == name {
+			return m.names[i].r, true
+		}
+	}
+	return Range{}, false
+}
+
+// freeNameSlot 空いている名前スロットを探す（なければ-1）
+func (m *Manager) freeNameSlot() int {
+	for i := range m.names {
+		if m.names[i].name == "" {
+			return i
+		}
+	}
+	return -1
+}
+
+// Set 色を設定（元の色とシャドウコピーの両方）
+func (m *Manager) Set(index uint8, color uint16) {
+	m.base[index] = color
+	m.shadow[index] = color
+	m.dirty = true
+}
+
+// Get シャドウコピーの色を取得
+func (m *Manager) Get(index uint8) uint16 {
+	return m.shadow[index]
+}
+
+// Base 元の色（エフェクト適用前）を取得
+func (m *Manager) Base(index uint8) uint16 {
+	return m.base[index]
+}
+
+// SetWorking シャドウコピーの色だけを設定（元の色は変えない）
+// エフェクトから毎フレーム上書きするときに使う
+func (m *Manager) SetWorking(index uint8, color uint16) {
+	m.shadow[index] = color
+	m.dirty = true
+}
+
+// Restore 範囲のシャドウコピーを元の色に戻す
+func (m *Manager) Restore(r Range) {
+	if !r.Valid() {
+		return
+	}
+	copy(m.shadow[r.Start:r.End()], m.base[r.Start:r.End()])
+	m.dirty = true
+}
+
+// Load パレットデータをstartから読み込む（範囲外は切り捨て）
+func (m *Manager) Load(start int, colors []uint16) {
+	if start < 0 || start >= Size {
+		return
+	}
+	copy(m.base[start:], colors)
+	copy(m.shadow[start:], colors)
+	m.dirty = true
+}
+
+// LoadRange 範囲にパレットデータを読み込む（範囲を超える分は切り捨て）
+func (m *Manager) LoadRange(r Range, colors []uint16) {
+	if !r.Valid() {
+		return
+	}
+	if len(colors) > r.Count {
+		colors = colors[:r.Count]
+	}
+	m.Load(r.Start, colors)
+}
+
+// Fill 範囲を1色で塗りつぶす
+func (m *Manager) Fill(r Range, color uint16) {
+	if !r.Valid() {
+		return
+	}
+	for i := r.Start; i < r.End(); i++ {
+		m.base[i] = color
+		m.shadow[i] = color
+	}
+	m.dirty = true
+}
+
+// Gradient 範囲をfromからtoへのグラデーションで埋める
+func (m *Manager) Gradient(r Range, from, to uint16) {
+	if !r.Valid() {
+		return
+	}
+	graphics.Gradient(m.base[r.Start:r.End()], from, to)
+	copy(m.shadow[r.Start:r.End()], m.base[r.Start:r.End()])
+	m.dirty = true
+}
+
+// Colors 範囲に対応するシャドウコピーのスライスを取得
+// 直接書き換えた場合はMarkDirtyを呼ぶこと（元の色は変わらない）
+// 範囲がパレットに収まっていなければnil
+func (m *Manager) Colors(r Range) []uint16 {
+	if !r.Valid() {
+		return nil
+	}
+	return m.shadow[r.Start:r.End()]
+}
+
+// MarkDirty 次のCommitで必ず転送させる
+func (m *Manager) MarkDirty() {
+	m.dirty = true
+}
+
+// Commit シャドウコピーをパレットRAMに転送（VBlank中に呼ぶ）
+func (m *Manager) Commit() {
+	if !m.dirty {
+		return
+	}
+	memory.DMA3Copy32(unsafe.Pointer(m.addr), unsafe.Pointer(&m.shadow[0]), Size/2)
+	m.dirty = false
+}
@@ -0,0 +1,109 @@
+package particle
+
+import (
+	"github.com/ryomak/gameboys/common/gba/graphics"
+	"github.com/ryomak/gameboys/common/gba/palette"
+	"github.com/ryomak/gameboys/common/math"
+)
+
+// drawMode4 1つのパーティクルを点または小さな矩形で描画（中心が(x, y)）
+func drawMode4(x, y int, size int, colorIndex uint8) {
+	if size <= 1 {
+		graphics.SetMode4Pixel(x, y, colorIndex)
+		return
+	}
+	graphics.FillRectMode4(x-size/2, y-size/2, size, size, colorIndex)
+}
+
+// DrawMode4 2Dのパーティクル（座標は画面座標）をMode 4で描画
+func (s *System) DrawMode4() {
+	for i := 0; i < s.count; i++ {
+		p := &s.particles[i]
+		drawMode4(int(p.Pos.X.Round()), int(p.Pos.Y.Round()), int(p.Size), p.Color())
+	}
+}
+
+// Draw 2DのパーティクルをMode 3で描画
+// ランプのパレットインデックスはpalの色に変換する
+func (s *System) Draw(pal *palette.Manager) {
+	for i := 0; i < s.count; i++ {
+		p := &s.particles[i]
+		x := int(p.Pos.X.Round())
+		y := int(p.Pos.Y.Round())
+		color := pal.Get(p.Color())
+		if p.Size <= 1 {
+			graphics.DrawPixel(x, y, color)
+			continue
+		}
+		size := int(p.Size)
+		graphics.FillRect(x-size/2, y-size/2, size, size, color)
+	}
+}
+
+// DrawProjectedMode4 3Dのパーティクル（座標はワールド座標）を射影してMode 4で描画
+// サイズは奥行きに応じて縮む（最小1ピクセル）
+func (s *System) DrawProjectedMode4(view *math.View) {
+	for i := 0; i < s.count; i++ {
+		p := &s.particles[i]
+		result := view.ProjectView(view.ToView(p.Pos), graphics.Mode4Width, graphics.Mode4Height)
+		if !result.Visible {
+			continue
+		}
+		size := result.Scale.Mul(math.NewFixed(int32(p.Size))).Round()
+		drawMode4(int(result.ScreenX), int(result.ScreenY), int(size), p.Color())
+	}
+}
+
+// SpriteTarget パーティクルを置くスプライトの並び（OAMのエントリなど）
+type SpriteTarget interface {
+	Len() int                                // 使えるスプライトの数
+	Place(i int, x, y int, colorIndex uint8) // i番目を中心が(x, y)になるように置いて表示する
+	Hide(i int)                              // i番目を非表示にする
+}
+
+// DrawSprites 2Dのパーティクルをtargetのスプライトに先頭から置く
+// 余ったスプライトは非表示にし、足りずに置けなかったパーティクルの数を返す
+func (s *System) DrawSprites(target SpriteTarget) int {
+	next := 0
+	dropped := 0
+	for i := 0; i < s.count; i++ {
+		p := &s.particles[i]
+		if next >= target.Len() {
+			dropped++
+			continue
+		}
+		target.Place(next, int(p.Pos.X.Round()), int(p.Pos.Y.Round()), p.Color())
+		next++
+	}
+	hideSprites(target, next)
+	return dropped
+}
+
+// DrawProjectedSprites 3Dのパーティクルを射影してtargetのスプライトに置く
+// スプライトの大きさは変えない（奥行きで縮めたい場合はtargetが色ごとに小さい絵を使う）
+func (s *System) DrawProjectedSprites(view *math.View, target SpriteTarget) int {
+	next := 0
+	dropped := 0
+	for i := 0; i < s.count; i++ {
+		p := &s.particles[i]
+		result := view.ProjectView(view.ToView(p.Pos), graphics.Mode4Width, graphics.Mode4Height)
+		if !result.Visible {
+			continue
+		}
+		if next >= target.Len() {
+			dropped++
+			continue
+		}
+		target.Place(next, int(result.ScreenX), int(result.ScreenY), p.Color())
+		next++
+	}
+	hideSprites(target, next)
+	return dropped
+}
+
+// hideSprites from番目以降の使わなかったスプライトを非表示にする
+func hideSprites(target SpriteTarget, from int) {
+	for i := from; i < target.Len(); i++ {
+		target.Hide(i)
+	}
+}
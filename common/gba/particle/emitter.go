@@ -0,0 +1,93 @@
+package particle
+
+import (
+	"github.com/ryomak/gameboys/common/gba/palette"
+	"github.com/ryomak/gameboys/common/math"
+)
+
+// Emitter パーティクルの発生源
+// 各成分のばらつき（Area、Spread）は±の幅で、発生ごとに一様乱数で加える
+type Emitter struct {
+	Position math.Vec3
+	Area     math.Vec3 // 発生位置のばらつき
+	Velocity math.Vec3 // 初速度
+	Spread   math.Vec3 // 初速度のばらつき
+	Gravity  math.Vec3 // 毎フレーム速度に加える値
+
+	Rate       math.Fixed // 1フレームに発生させる数（0.25なら4フレームに1個）
+	Life       uint16     // 寿命（フレーム数）
+	LifeSpread uint16     // 寿命のばらつき（0からこの値までを加える）
+	Ramp       palette.Range
+	Size       uint8
+
+	Active bool // trueの間、UpdateでRateに従って発生させる
+
+	accum math.Fixed // 発生数の端数
+}
+
+// Set2D 2Dの位置・初速度・ばらつきを設定（Zは0）
+func (e *Emitter) Set2D(position, velocity, spread math.Vec2) {
+	e.Position = math.Vec3{X: position.X, Y: position.Y}
+	e.Velocity = math.Vec3{X: velocity.X, Y: velocity.Y}
+	e.Spread = math.Vec3{X: spread.X, Y: spread.Y}
+}
+
+// Emit パーティクルを1つ発生させる（満杯ならfalse）
+func (e *Emitter) Emit(s *System) bool {
+	p := s.Spawn()
+	if p == nil {
+		return false
+	}
+	p.Pos = e.Position.Add(jitter3(e.Area))
+	p.Vel = e.Velocity.Add(jitter3(e.Spread))
+	p.Accel = e.Gravity
+	p.Life = e.Life
+	if e.LifeSpread > 0 {
+		p.Life += uint16(math.RandInt(int32(e.LifeSpread) + 1))
+	}
+	if p.Life == 0 {
+		p.Life = 1
+	}
+	p.Ramp = e.Ramp
+	p.Size = e.Size
+	return true
+}
+
+// Burst n個をまとめて発生させ、発生できた数を返す
+func (e *Emitter) Burst(s *System, n int) int {
+	for i := 0; i < n; i++ {
+		if !e.Emit(s) {
+			return i
+		}
+	}
+	return n
+}
+
+// Update 1フレーム分、Rateに従って発生させる
+func (e *Emitter) Update(s *System) {
+	if !e.Active {
+		e.accum = 0
+		return
+	}
+	e.accum = e.accum.Add(e.Rate)
+	for e.accum >= math.FixedOne {
+		e.accum = e.accum.Sub(math.FixedOne)
+		if !e.Emit(s) {
+			e.accum = 0
+			return
+		}
+	}
+}
+
+// jitter -v以上v未満の乱数
+func jitter(v math.Fixed) math.Fixed {
+	if v == 0 {
+		return 0
+	}
+	return math.RandFixedRange(-v, v)
+}
+
+// jitter3 成分ごとのばらつき
+func jitter3(v math.Vec3) math.Vec3 {
+	return math.Vec3{X: jitter(v.X), Y: jitter(v.Y), Z: jitter(v.Z)}
+}
@@ -0,0 +1,101 @@
+package particle
+
+import (
+	"github.com/ryomak/gameboys/common/gba/palette"
+	"github.com/ryomak/gameboys/common/math"
+)
+
+// 固定数のパーティクルシステム
+// 初期化時にバッファを1回だけ確保し、フレーム中はメモリを確保しない
+// 座標・速度の単位は自由（2Dでは画面座標でZは0、3Dではワールド座標）、速度は1フレームあたりの移動量
+
+// Particle 1つの粒子
+type Particle struct {
+	Pos   math.Vec3
+	Vel   math.Vec3
+	Accel math.Vec3 // 毎フレーム速度に加える値（重力など）
+	Age   uint16    // 生まれてからのフレーム数
+	Life  uint16    // 寿命（フレーム数）
+	Ramp  palette.Range
+	Size  uint8 // 描画サイズ（1以下なら1ピクセル）
+}
+
+// Color 経過時間に応じたランプの色（先頭が生まれた直後、末尾が消える直前）
+func (p *Particle) Color() uint8 {
+	if p.Ramp.Count <= 1 || p.Life == 0 {
+		return p.Ramp.Index(0)
+	}
+	i := int(p.Age) * p.Ramp.Count / int(p.Life)
+	if i >= p.Ramp.Count {
+		i = p.Ramp.Count - 1
+	}
+	return p.Ramp.Index(i)
+}
+
+// System パーティクルの集合
+// 生きているパーティクルはparticles[:count]に詰めて保持する
+type System struct {
+	Drag math.Fixed // 毎フレームの速度の減衰率（0で減衰なし）
+
+	particles []Particle
+	count     int
+}
+
+// NewSystem 指定した数まで保持できるシステムを作成（初期化時に使う）
+func NewSystem(capacity int) *System {
+	return &System{particles: make([]Particle, capacity)}
+}
+
+// Spawn 新しいパーティクルを取得（満杯ならnil）
+// 返されたパーティクルは0で初期化されている
+func (s *System) Spawn() *Particle {
+	if s.count >= len(s.particles) {
+		return nil
+	}
+	p := &s.particles[s.count]
+	*p = Particle{}
+	s.count++
+	return p
+}
+
+// Update 1フレーム分進め、寿命の尽きたパーティクルを取り除く
+func (s *System) Update() {
+	damping := math.FixedOne.Sub(s.Drag)
+	i := 0
+	for i < s.count {
+		p := &s.particles[i]
+		p.Age++
+		if p.Age >= p.Life {
+			// 末尾と入れ替えて詰める
+			s.count--
+			s.particles[i] = s.particles[s.count]
+			continue
+		}
+		p.Vel = p.Vel.Add(p.Accel)
+		if s.Drag != 0 {
+			p.Vel = p.Vel.Mul(damping)
+		}
+		p.Pos = p.Pos.Add(p.Vel)
+		i++
+	}
+}
+
+// Particles 生きているパーティクル（スプライトで描画する場合などに使う）
+func (s *System) Particles() []Particle {
+	return s.particles[:s.count]
+}
+
+// Count 生きているパーティクルの数
+func (s *System) Count() int {
+	return s.count
+}
+
+// Capacity 保持できる最大数
+func (s *System) Capacity() int {
+	return len(s.particles)
+}
+
+// Clear すべてのパーティクルを消す
+func (s *System) Clear() {
+	s.count = 0
+}
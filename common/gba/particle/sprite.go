@@ -0,0 +1,34 @@
+package particle
+
+import "github.com/ryomak/gameboys/common/gba/sprite"
+
+// ManagerSprites sprite.Managerのハンドルの並びをパーティクルの置き場所にする（SpriteTarget）
+// 各パーティクルは8x8の256色（8bpp）スプライトになる
+type ManagerSprites struct {
+	Manager *sprite.Manager
+	Handles []sprite.Handle
+
+	// Tile パーティクルの色（ランプのパレットインデックス）からタイル番号を返す
+	// Mode 3-5ではsprite.BitmapTileStart以降に読み込んだタイルを使う
+	Tile func(colorIndex uint8) int
+}
+
+// Len 使えるスプライトの数
+func (s *ManagerSprites) Len() int {
+	return len(s.Handles)
+}
+
+// Place i番目のスプライトを中心が(x, y)になるように置いて表示する
+func (s *ManagerSprites) Place(i int, x, y int, colorIndex uint8) {
+	a := s.Manager.Sprite(s.Handles[i])
+	a.SetSize(sprite.Size8x8)
+	a.SetColor256(true)
+	a.SetTile(s.Tile(colorIndex))
+	a.SetPosition(x-4, y-4)
+	a.Show()
+}
+
+// Hide i番目のスプライトを非表示にする
+func (s *ManagerSprites) Hide(i int) {
+	s.Manager.Sprite(s.Handles[i]).Hide()
+}
@@ -0,0 +1,93 @@
+package power
+
+import (
+	"runtime/volatile"
+	"unsafe"
+
+	"github.com/ryomak/gameboys/common/gba/bios"
+	"github.com/ryomak/gameboys/common/gba/display"
+	"github.com/ryomak/gameboys/common/gba/input"
+	"github.com/ryomak/gameboys/common/gba/interrupt"
+)
+
+// スリープ（低消費電力の停止状態）
+// 画面と音を止めてBIOSのStopに入り、キー割り込みで起きたら元に戻す
+
+// SleepKeys 標準のスリープ・復帰のキー（L+R+Select）
+const SleepKeys = input.KeyL | input.KeyR | input.KeySelect
+
+// 音のレジスタ
+const (
+	RegSOUND1CNT_L = 0x04000060 // チャンネル1-4の設定の先頭
+	RegSOUNDCNT_H  = 0x04000082 // DMAサウンドとミキサーの設定
+	RegSOUNDCNT_X  = 0x04000084 // 音の全体の制御
+	soundEnable    = 1 << 7
+
+	// soundRegs 保存するレジスタの数（SOUND1CNT_LからSOUNDCNT_Hまで、2バイトずつ）
+	soundRegs = (RegSOUNDCNT_H-RegSOUND1CNT_L)/2 + 1
+)
+
+var SOUNDCNT_X = (*volatile.Register16)(unsafe.Pointer(uintptr(RegSOUNDCNT_X)))
+
+// soundReg SOUND1CNT_Lからi番目（2バイト単位）のレジスタ
+func soundReg(i int) *volatile.Register16 {
+	return (*volatile.Register16)(unsafe.Pointer(uintptr(RegSOUND1CNT_L + i*2)))
+}
+
+// Sleep スリープし、wakeKeysがすべて押されたら復帰する
+// スリープ中はキー割り込みの設定を使い、復帰後に止める（キー割り込みを使っている場合は設定し直す）
+// 音を止めると0x04000060-0x04000081がクリアされるため、読めるレジスタを保存して戻す
+// 周波数・長さ・開始のビットは書き込み専用で戻せないので、鳴っていた音は復帰後に鳴らし直す
+func Sleep(wakeKeys uint16) {
+	// スリープに入ったキーで、すぐに復帰しないよう離されるのを待つ
+	waitRelease(wakeKeys)
+
+	dispcnt := display.GetControl()
+	sound := SOUNDCNT_X.Get()
+	var soundState [soundRegs]uint16
+	for i := range soundState {
+		soundState[i] = soundReg(i).Get()
+	}
+
+	// 画面（強制ブランク）と音を止める
+	display.WaitForVBlank()
+	display.SetControl(dispcnt | display.ForcedBlank)
+	SOUNDCNT_X.Set(sound &^ soundEnable)
+
+	input.SetKeyInterrupt(wakeKeys, true)
+	interrupt.Set(interrupt.Keypad, wake)
+	bios.Stop()
+	interrupt.Disable(interrupt.Keypad)
+	input.DisableKeyInterrupt()
+
+	// 復帰したキーの入力がゲームに伝わらないよう、離されてから戻す
+	waitRelease(wakeKeys)
+	// 全体を有効にしてから各チャンネルを書き戻す（無効の間は書き込めない）
+	SOUNDCNT_X.Set(sound)
+	for i, v := range soundState {
+		soundReg(i).Set(v)
+	}
+	display.WaitForVBlank()
+	display.SetControl(dispcnt)
+}
+
+// CheckSleep SleepKeysがそろった瞬間にスリープする（KeyState.Updateの後に毎フレーム呼ぶ）
+// スリープした場合はtrueを返す
+func CheckSleep(ks *input.KeyState) bool {
+	// すべて押されていて、そのうちどれかがこのフレームで押された
+	if !ks.IsHeld(SleepKeys) || ks.GetPressedKeys()&SleepKeys == 0 {
+		return false
+	}
+	Sleep(SleepKeys)
+	return true
+}
+
+// waitRelease keysのいずれかが押されている間待つ
+func waitRelease(keys uint16) {
+	for input.IsAnyKeyDown(keys) {
+		display.WaitForVBlank()
+	}
+}
+
+// wake キー割り込みの処理（Stopから戻るだけ）
+func wake() {}
@@ -0,0 +1,206 @@
+package render3d
+
+import (
+	"github.com/ryomak/gameboys/common/gba/graphics"
+	"github.com/ryomak/gameboys/common/math"
+)
+
+// 3Dの線分・ワイヤーフレームの描画（デバッグ表示やレベルデザイン用）
+// 線分はニアプレーンで切ってから射影するため、カメラの後ろへ伸びる線も途中まで描かれる
+
+// CircleSegments 円を近似する線分の数
+const CircleSegments = 16
+
+// Plane 円やグリッドを置く平面
+type Plane uint8
+
+const (
+	PlaneXZ Plane = iota // 水平面（床）
+	PlaneXY              // 正面
+	PlaneYZ              // 側面
+)
+
+// wire 3Dの線分の描画先（Mode 3/4で共通）
+type wire struct {
+	view  *math.View
+	mode4 bool
+	color uint16 // Mode 3では15bitカラー、Mode 4ではパレットインデックス
+}
+
+// line ワールド座標の線分を描画
+func (w wire) line(a, b math.Vec3) {
+	va := w.view.ToView(a)
+	vb := w.view.ToView(b)
+
+	// ニアプレーンで切る
+	near := w.view.Near
+	if va.Z < near && vb.Z < near {
+		return
+	}
+	if va.Z < near {
+		va = clipNear(vb, va, near)
+	} else if vb.Z < near {
+		vb = clipNear(va, vb, near)
+	}
+
+	x0, y0 := projectLine(w.view, va)
+	x1, y1 := projectLine(w.view, vb)
+	if w.mode4 {
+		graphics.DrawLineMode4(x0, y0, x1, y1, uint8(w.color))
+	} else {
+		graphics.DrawLine(x0, y0, x1, y1, w.color)
+	}
+}
+
+// clipNear ニアプレーンの奥にあるinsideと手前にあるoutsideを結ぶ線分の、ニアプレーン上の点
+func clipNear(inside, outside math.Vec3, near math.Fixed) math.Vec3 {
+	t := near.Sub(inside.Z).Div(outside.Z.Sub(inside.Z))
+	p := math.Lerp3(inside, outside, t)
+	p.Z = near
+	return p
+}
+
+// projectLine ビュー空間の点を画面座標に変換（Mode 3/4は同じ240x160）
+// 画面外の座標も線の向きを保つためそのまま返す（はみ出た部分はDrawLineが切る）
+func projectLine(view *math.View, p math.Vec3) (int, int) {
+	result := view.ProjectView(p, graphics.ScreenWidth, graphics.ScreenHeight)
+	return int(result.ScreenX), int(result.ScreenY)
+}
+
+// box 直方体の12本の辺を描画
+func (w wire) box(min, max math.Vec3) {
+	var corners [8]math.Vec3
+	for i := range corners {
+		c := min
+		if i&1 != 0 {
+			c.X = max.X
+		}
+		if i&2 != 0 {
+			c.Y = max.Y
+		}
+		if i&4 != 0 {
+			c.Z = max.Z
+		}
+		corners[i] = c
+	}
+	for i := 0; i < 8; i++ {
+		// 各軸方向に隣り合う頂点と結ぶ（1つの辺は1回だけ描く）
+		for bit := 1; bit < 8; bit <<= 1 {
+			if i&bit == 0 {
+				w.line(corners[i], corners[i|bit])
+			}
+		}
+	}
+}
+
+// planeAxes 平面を張る2つの軸
+func planeAxes(plane Plane, u, v math.Fixed) math.Vec3 {
+	switch plane {
+	case PlaneXY:
+		return math.Vec3{X: u, Y: v}
+	case PlaneYZ:
+		return math.Vec3{Y: u, Z: v}
+	default:
+		return math.Vec3{X: u, Z: v}
+	}
+}
+
+// circle 平面上の円を描画
+func (w wire) circle(center math.Vec3, radius math.Fixed, plane Plane) {
+	const step = 256 / CircleSegments
+	prev := center.Add(planeAxes(plane, radius, 0))
+	for i := 1; i <= CircleSegments; i++ {
+		angle := int32(i * step)
+		p := center.Add(planeAxes(plane, radius.Mul(math.Cos(angle)), radius.Mul(math.Sin(angle))))
+		w.line(prev, p)
+		prev = p
+	}
+}
+
+// grid 平面上の格子を描画
+func (w wire) grid(center math.Vec3, size math.Fixed, divisions int, plane Plane) {
+	if divisions < 1 {
+		divisions = 1
+	}
+	half := size >> 1
+	for i := 0; i <= divisions; i++ {
+		offset := size.Mul(math.NewFixed(int32(i))).Div(math.NewFixed(int32(divisions))).Sub(half)
+		w.line(center.Add(planeAxes(plane, offset, -half)), center.Add(planeAxes(plane, offset, half)))
+		w.line(center.Add(planeAxes(plane, -half, offset)), center.Add(planeAxes(plane, half, offset)))
+	}
+}
+
+// polyline 点を順に結ぶ
+func (w wire) polyline(points []math.Vec3) {
+	for i := 1; i < len(points); i++ {
+		w.line(points[i-1], points[i])
+	}
+}
+
+// DrawLine3D ワールド座標の線分を描画
+func DrawLine3D(view *math.View, a, b math.Vec3, color uint16) {
+	wire{view: view, color: color}.line(a, b)
+}
+
+// DrawLine3DMode4 Mode 4で線分を描画
+func DrawLine3DMode4(view *math.View, a, b math.Vec3, colorIndex uint8) {
+	wire{view: view, mode4: true, color: uint16(colorIndex)}.line(a, b)
+}
+
+// DrawPolyline3D 点を順に結んだ折れ線を描画（軌道の表示など）
+func DrawPolyline3D(view *math.View, points []math.Vec3, color uint16) {
+	wire{view: view, color: color}.polyline(points)
+}
+
+// DrawPolyline3DMode4 Mode 4で折れ線を描画
+func DrawPolyline3DMode4(view *math.View, points []math.Vec3, colorIndex uint8) {
+	wire{view: view, mode4: true, color: uint16(colorIndex)}.polyline(points)
+}
+
+// DrawBox3D 軸に平行な直方体をワイヤーフレームで描画（当たり判定の表示など）
+// min, max: 対角の2頂点
+func DrawBox3D(view *math.View, min, max math.Vec3, color uint16) {
+	wire{view: view, color: color}.box(min, max)
+}
+
+// DrawBox3DMode4 Mode 4で直方体を描画
+func DrawBox3DMode4(view *math.View, min, max math.Vec3, colorIndex uint8) {
+	wire{view: view, mode4: true, color: uint16(colorIndex)}.box(min, max)
+}
+
+// DrawCircle3D 平面上の円を描画
+func DrawCircle3D(view *math.View, center math.Vec3, radius math.Fixed, plane Plane, color uint16) {
+	wire{view: view, color: color}.circle(center, radius, plane)
+}
+
+// DrawCircle3DMode4 Mode 4で円を描画
+func DrawCircle3DMode4(view *math.View, center math.Vec3, radius math.Fixed, plane Plane, colorIndex uint8) {
+	wire{view: view, mode4: true, color: uint16(colorIndex)}.circle(center, radius, plane)
+}
+
+// DrawSphere3D 球を3つの平面の円で描画
+func DrawSphere3D(view *math.View, center math.Vec3, radius math.Fixed, color uint16) {
+	w := wire{view: view, color: color}
+	w.circle(center, radius, PlaneXZ)
+	w.circle(center, radius, PlaneXY)
+	w.circle(center, radius, PlaneYZ)
+}
+
+// DrawSphere3DMode4 Mode 4で球を描画
+func DrawSphere3DMode4(view *math.View, center math.Vec3, radius math.Fixed, colorIndex uint8) {
+	w := wire{view: view, mode4: true, color: uint16(colorIndex)}
+	w.circle(center, radius, PlaneXZ)
+	w.circle(center, radius, PlaneXY)
+	w.circle(center, radius, PlaneYZ)
+}
+
+// DrawGrid3D 平面上の格子を描画
+// size: 一辺の長さ、divisions: 分割数
+func DrawGrid3D(view *math.View, center math.Vec3, size math.Fixed, divisions int, plane Plane, color uint16) {
+	wire{view: view, color: color}.grid(center, size, divisions, plane)
+}
+
+// DrawGrid3DMode4 Mode 4で格子を描画
+func DrawGrid3DMode4(view *math.View, center math.Vec3, size math.Fixed, divisions int, plane Plane, colorIndex uint8) {
+	wire{view: view, mode4: true, color: uint16(colorIndex)}.grid(center, size, divisions, plane)
+}
@@ -0,0 +1,66 @@
+package render3d
+
+import "github.com/ryomak/gameboys/common/math"
+
+// Face 三角形の面
+// 頂点は表から見て時計回りに並べる（裏向きの面は描画されない）
+type Face struct {
+	A, B, C  uint16 // 頂点のインデックス
+	Material uint8  // 使うシェードランプの番号（Renderer.SetMaterial）
+}
+
+// Mesh 頂点と面のリスト
+type Mesh struct {
+	Vertices []math.Vec3 // モデル座標の頂点
+	Faces    []Face
+}
+
+// Transform モデル座標からワールド座標への変換
+// 拡大縮小、回転、平行移動の順に適用する
+type Transform struct {
+	Position math.Vec3
+	Rotation math.Mat3
+	Scale    math.Fixed
+}
+
+// NewTransform 位置だけを指定した変換を作成（回転なし、等倍）
+func NewTransform(position math.Vec3) Transform {
+	return Transform{
+		Position: position,
+		Rotation: math.Identity3(),
+		Scale:    math.FixedOne,
+	}
+}
+
+// SetRotation ヨー・ピッチ・ロール（0-255 が 0-360度）で回転を設定
+func (t *Transform) SetRotation(yaw, pitch, roll int32) {
+	t.Rotation = math.RotationYXZ(yaw, pitch, roll)
+}
+
+// Apply モデル座標の点をワールド座標に変換
+func (t *Transform) Apply(v math.Vec3) math.Vec3 {
+	if t.Scale != math.FixedOne {
+		v = v.Mul(t.Scale)
+	}
+	return t.Rotation.MulVec3(v).Add(t.Position)
+}
+
+// NewBoxMesh 中心が原点の直方体を作成（初期化時に使う）
+// w, h, d: 幅・高さ・奥行き
+func NewBoxMesh(w, h, d math.Fixed, material uint8) *Mesh {
+	x, y, z := w>>1, h>>1, d>>1
+	return &Mesh{
+		Vertices: []math.Vec3{
+			{X: -x, Y: -y, Z: -z}, {X: x, Y: -y, Z: -z}, {X: x, Y: y, Z: -z}, {X: -x, Y: y, Z: -z}, // 手前
+			{X: -x, Y: -y, Z: z}, {X: x, Y: -y, Z: z}, {X: x, Y: y, Z: z}, {X: -x, Y: y, Z: z}, // 奥
+		},
+		Faces: []Face{
+			{0, 3, 2, material}, {0, 2, 1, material}, // 手前（-Z）
+			{5, 6, 7, material}, {5, 7, 4, material}, // 奥（+Z）
+			{4, 7, 3, material}, {4, 3, 0, material}, // 左（-X）
+			{1, 2, 6, material}, {1, 6, 5, material}, // 右（+X）
+			{3, 7, 6, material}, {3, 6, 2, material}, // 上（+Y）
+			{4, 0, 1, material}, {4, 1, 5, material}, // 下（-Y）
+		},
+	}
+}
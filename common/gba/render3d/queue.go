@@ -0,0 +1,162 @@
+package render3d
+
+import (
+	"github.com/ryomak/gameboys/common/gba/graphics"
+	"github.com/ryomak/gameboys/common/gba/sprite"
+	"github.com/ryomak/gameboys/common/math"
+)
+
+// 奥行きでソートする描画キュー（擬似3D用）
+// ワールド座標の物体をカメラで射影して画面外のものを除き、奥から順に並べて
+// ビットマップに描くか、スプライトにOAMの番号（番号の小さい方が前面）と優先度を割り当てる
+// スプライトは常にビットマップ（BG2）より前に表示されるため、前後を入れ替えたい物体同士はどちらもスプライトにする
+
+// MaxQueueItems 1フレームに追加できる物体の最大数
+const MaxQueueItems = 64
+
+// BitmapDrawer ビットマップに描く物体
+type BitmapDrawer interface {
+	DrawBitmap(p math.ProjectionResult)
+}
+
+// SpriteDrawer スプライトで表示する物体
+// 割り当てられたスプライトは初期状態（8x8、タイル0、非表示）なので、大きさやタイルも毎回設定する
+type SpriteDrawer interface {
+	PlaceSprite(m *sprite.Manager, h sprite.Handle, p math.ProjectionResult)
+}
+
+// BitmapFunc 関数をBitmapDrawerとして使う
+type BitmapFunc func(p math.ProjectionResult)
+
+// DrawBitmap fを呼ぶ
+func (f BitmapFunc) DrawBitmap(p math.ProjectionResult) {
+	f(p)
+}
+
+// SpriteFunc 関数をSpriteDrawerとして使う
+type SpriteFunc func(m *sprite.Manager, h sprite.Handle, p math.ProjectionResult)
+
+// PlaceSprite fを呼ぶ
+func (f SpriteFunc) PlaceSprite(m *sprite.Manager, h sprite.Handle, p math.ProjectionResult) {
+	f(m, h, p)
+}
+
+// Item キューに追加する物体（BitmapかSpriteのどちらかを設定する）
+type Item struct {
+	Pos      math.Vec3    // ワールド座標（射影と奥行きの基準）
+	Bias     math.Fixed   // 奥行きに足す値（負で手前に並ぶ。同じ位置の物体の前後を決める）
+	Bitmap   BitmapDrawer // ビットマップに描く場合
+	Sprite   SpriteDrawer // スプライトで表示する場合
+	Priority int          // スプライトの背景との優先度（0-3）
+}
+
+// queued 射影済みの物体
+type queued struct {
+	item   *Item
+	result math.ProjectionResult
+	depth  math.Fixed // ビュー空間の奥行き（Biasを足したもの）
+}
+
+// QueueStats 直前のフレームの統計
+type QueueStats struct {
+	Drawn   int // ビットマップに描いた物体
+	Sprites int // スプライトを割り当てた物体
+	Culled  int // 画面外・ニアプレーンの手前・ファープレーンの奥で除いた物体
+	Dropped int // キューが一杯か、スプライトが足りずに捨てた物体
+}
+
+// Queue 奥行きソートの描画キュー
+type Queue struct {
+	Stats QueueStats
+
+	view  math.View
+	items [MaxQueueItems]queued
+	order [MaxQueueItems]uint8
+	count int
+}
+
+// NewQueue 描画キューを作成
+func NewQueue() *Queue {
+	return &Queue{}
+}
+
+// Begin フレームの追加を開始
+func (q *Queue) Begin(camera *math.Camera) {
+	q.view = camera.View()
+	q.count = 0
+	q.Stats = QueueStats{}
+}
+
+// View 現在のフレームのビュー変換
+func (q *Queue) View() *math.View {
+	return &q.view
+}
+
+// Add 物体を射影してキューに追加（表示されない場合はfalse）
+// itemはFlushまで参照するため、フレームをまたいで残る場所に置く
+func (q *Queue) Add(item *Item) bool {
+	viewPos := q.view.ToView(item.Pos)
+	result := q.view.ProjectView(viewPos, graphics.ScreenWidth, graphics.ScreenHeight)
+	if !result.Visible {
+		q.Stats.Culled++
+		return false
+	}
+	if q.count >= MaxQueueItems {
+		q.Stats.Dropped++
+		return false
+	}
+	q.items[q.count] = queued{item: item, result: result, depth: viewPos.Z.Add(item.Bias)}
+	q.order[q.count] = uint8(q.count)
+	q.count++
+	return true
+}
+
+// Flush 奥から順にビットマップに描き、スプライトには手前から順にhandlesを割り当てる
+// handlesは番号の小さい順に並べる（余ったスプライトは非表示にする）
+// スプライトを使わない場合はmをnilにできる
+func (q *Queue) Flush(m *sprite.Manager, handles []sprite.Handle) {
+	// 奥行きの降順に挿入ソート（MaxQueueItems個までなので単純な方法で足りる）
+	for i := 1; i < q.count; i++ {
+		key := q.order[i]
+		depth := q.items[key].depth
+		j := i - 1
+		for j >= 0 && q.items[q.order[j]].depth < depth {
+			q.order[j+1] = q.order[j]
+			j--
+		}
+		q.order[j+1] = key
+	}
+
+	for i := 0; i < q.count; i++ {
+		e := &q.items[q.order[i]]
+		if e.item.Bitmap != nil {
+			e.item.Bitmap.DrawBitmap(e.result)
+			q.Stats.Drawn++
+		}
+	}
+
+	next := 0
+	if m != nil {
+		for i := q.count - 1; i >= 0; i-- {
+			e := &q.items[q.order[i]]
+			if e.item.Sprite == nil {
+				continue
+			}
+			if next >= len(handles) {
+				q.Stats.Dropped++
+				continue
+			}
+			h := handles[next]
+			next++
+			s := m.Sprite(h)
+			s.Reset()
+			e.item.Sprite.PlaceSprite(m, h, e.result)
+			s.SetPriority(e.item.Priority)
+			q.Stats.Sprites++
+		}
+		for ; next < len(handles); next++ {
+			m.Sprite(handles[next]).Hide()
+		}
+	}
+	q.count = 0
+}
@@ -0,0 +1,267 @@
+package render3d
+
+import (
+	"github.com/ryomak/gameboys/common/gba/graphics"
+	"github.com/ryomak/gameboys/common/gba/palette"
+	"github.com/ryomak/gameboys/common/math"
+)
+
+// Mode 4用のフラットシェーディング3Dレンダラー
+// メッシュをビュー空間に変換し、裏面カリング・ニアクリップ・陰影計算をして三角形を溜め、
+// Flushで奥から順に（画家のアルゴリズム）塗りつぶす
+
+const (
+	MaxVertices  = 256 // 1メッシュの最大頂点数
+	MaxTriangles = 256 // 1フレームに溜められる三角形の最大数
+	MaxMaterials = 16  // シェードランプの最大数
+)
+
+// screenLimit 射影後の座標の上限（三角形描画での桁あふれを防ぐ）
+const screenLimit = 8192
+
+// triangle 描画待ちの三角形
+type triangle struct {
+	x0, y0, x1, y1, x2, y2 int16
+	depth                  math.Fixed // 奥行き（3頂点の平均）
+	color                  uint8
+}
+
+// Stats 直前のフレームの統計
+type Stats struct {
+	Drawn   int // 描画した三角形
+	Culled  int // 裏面カリングで除いた面
+	Clipped int // ニアプレーンで切った面
+	Dropped int // バッファが一杯で捨てた三角形
+}
+
+// Renderer 3Dレンダラー
+type Renderer struct {
+	Light   math.Vec3  // 光の来る方向（ワールド空間、正規化は不要）
+	Ambient math.Fixed // 環境光（0-FixedOne、裏を向いた面の明るさ）
+	Stats   Stats
+
+	view      math.View
+	lightView math.Vec3 // ビュー空間での光の方向（正規化済み）
+	materials [MaxMaterials]palette.Range
+
+	verts [MaxVertices]math.Vec3 // ビュー空間に変換した頂点
+	tris  [MaxTriangles]triangle
+	order [MaxTriangles]uint16
+	count int
+}
+
+// NewRenderer レンダラーを作成
+// 作業バッファが大きいため、IWRAMではなくヒープ（EWRAM）に確保する
+func NewRenderer() *Renderer {
+	return &Renderer{
+		Light:   math.NewVec3(1, 2, -2),
+		Ambient: math.FixedOne / 4,
+	}
+}
+
+// SetMaterial シェードランプを設定
+// ランプは暗い色から明るい色の順に並んだパレットの範囲（palette.Manager.Gradientで作る）
+func (r *Renderer) SetMaterial(index uint8, ramp palette.Range) {
+	if int(index) < MaxMaterials {
+		r.materials[index] = ramp
+	}
+}
+
+// Begin フレームの描画を開始
+func (r *Renderer) Begin(camera *math.Camera) {
+	r.view = camera.View()
+	r.lightView = r.view.DirectionToView(r.Light.Normalize()).Normalize()
+	r.count = 0
+	r.Stats = Stats{}
+}
+
+// View 現在のフレームのビュー変換
+func (r *Renderer) View() *math.View {
+	return &r.view
+}
+
+// Draw メッシュを変換して描画待ちに追加
+func (r *Renderer) Draw(mesh *Mesh, t *Transform) {
+	n := len(mesh.Vertices)
+	if n > MaxVertices {
+		n = MaxVertices
+	}
+	for i := 0; i < n; i++ {
+		world := mesh.Vertices[i]
+		if t != nil {
+			world = t.Apply(world)
+		}
+		r.verts[i] = r.view.ToView(world)
+	}
+
+	for _, f := range mesh.Faces {
+		if int(f.A) >= n || int(f.B) >= n || int(f.C) >= n {
+			continue
+		}
+		r.addFace(r.verts[f.A], r.verts[f.B], r.verts[f.C], f.Material)
+	}
+}
+
+// addFace 1つの面をカリング・陰影計算・ニアクリップして追加
+func (r *Renderer) addFace(a, b, c math.Vec3, material uint8) {
+	near := r.view.Near
+	far := r.view.Far
+
+	// 全体がニアプレーンの手前か、ファープレーンの奥なら描かない
+	if a.Z < near && b.Z < near && c.Z < near {
+		return
+	}
+	if far > 0 && a.Z > far && b.Z > far && c.Z > far {
+		return
+	}
+
+	// 裏面カリング（法線がカメラから頂点への向きと同じなら裏）
+	normal := faceNormal(a, b, c)
+	if normal.Dot(a) >= 0 {
+		r.Stats.Culled++
+		return
+	}
+
+	color := r.shade(normal, material)
+
+	// ニアクリップ（Sutherland-Hodgman、三角形は最大4頂点の多角形になる）
+	if a.Z >= near && b.Z >= near && c.Z >= near {
+		r.addTriangle(a, b, c, color)
+		return
+	}
+	r.Stats.Clipped++
+
+	in := [3]math.Vec3{a, b, c}
+	var out [4]math.Vec3
+	count := 0
+	for i := 0; i < 3; i++ {
+		p := in[i]
+		q := in[(i+1)%3]
+		pIn := p.Z >= near
+		qIn := q.Z >= near
+		if pIn {
+			out[count] = p
+			count++
+		}
+		if pIn != qIn {
+			// 辺とニアプレーンの交点
+			t := near.Sub(p.Z).Div(q.Z.Sub(p.Z))
+			out[count] = math.Lerp3(p, q, t)
+			out[count].Z = near
+			count++
+		}
+	}
+	if count >= 3 {
+		r.addTriangle(out[0], out[1], out[2], color)
+	}
+	if count == 4 {
+		r.addTriangle(out[0], out[2], out[3], color)
+	}
+}
+
+// faceNormal 面の法線（正規化済み）
+// 辺の外積は成分が大きいと16.16形式からあふれるため、先に辺を縮めてから計算する
+func faceNormal(a, b, c math.Vec3) math.Vec3 {
+	e1 := b.Sub(a)
+	e2 := c.Sub(a)
+	m := maxAbs(e1).Max(maxAbs(e2))
+	for m > math.NewFixed(64) {
+		e1 = math.Vec3{X: e1.X >> 1, Y: e1.Y >> 1, Z: e1.Z >> 1}
+		e2 = math.Vec3{X: e2.X >> 1, Y: e2.Y >> 1, Z: e2.Z >> 1}
+		m >>= 1
+	}
+	return e1.Cross(e2).Normalize()
+}
+
+// maxAbs ベクトルの成分の絶対値の最大
+func maxAbs(v math.Vec3) math.Fixed {
+	return v.X.Abs().Max(v.Y.Abs()).Max(v.Z.Abs())
+}
+
+// shade 法線と光の向きからシェードランプの色を選ぶ
+func (r *Renderer) shade(normal math.Vec3, material uint8) uint8 {
+	ramp := palette.Range{Count: 1}
+	if int(material) < MaxMaterials && r.materials[material].Count > 0 {
+		ramp = r.materials[material]
+	}
+
+	// 明るさ = 環境光 + (1 - 環境光) × max(0, N・L)
+	diffuse := normal.Dot(r.lightView)
+	if diffuse < 0 {
+		diffuse = 0
+	}
+	intensity := r.Ambient.Add(math.FixedOne.Sub(r.Ambient).Mul(diffuse))
+	intensity = intensity.Clamp(0, math.FixedOne)
+
+	step := intensity.Mul(math.NewFixed(int32(ramp.Count - 1))).Round()
+	return ramp.Index(int(step))
+}
+
+// addTriangle ビュー空間の三角形を射影して描画待ちに追加
+func (r *Renderer) addTriangle(a, b, c math.Vec3, color uint8) {
+	if r.count >= MaxTriangles {
+		r.Stats.Dropped++
+		return
+	}
+	x0, y0 := r.project(a)
+	x1, y1 := r.project(b)
+	x2, y2 := r.project(c)
+
+	// 三辺とも同じ側の画面外なら描かない
+	if (x0 < 0 && x1 < 0 && x2 < 0) || (y0 < 0 && y1 < 0 && y2 < 0) ||
+		(x0 >= graphics.Mode4Width && x1 >= graphics.Mode4Width && x2 >= graphics.Mode4Width) ||
+		(y0 >= graphics.Mode4Height && y1 >= graphics.Mode4Height && y2 >= graphics.Mode4Height) {
+		return
+	}
+
+	r.tris[r.count] = triangle{
+		x0: int16(x0), y0: int16(y0),
+		x1: int16(x1), y1: int16(y1),
+		x2: int16(x2), y2: int16(y2),
+		depth: (a.Z + b.Z + c.Z) / 3,
+		color: color,
+	}
+	r.order[r.count] = uint16(r.count)
+	r.count++
+}
+
+// project ビュー空間の点（ニアプレーンより奥）を画面座標に変換
+func (r *Renderer) project(p math.Vec3) (int, int) {
+	result := r.view.ProjectView(p, graphics.Mode4Width, graphics.Mode4Height)
+	x := int(result.ScreenX)
+	y := int(result.ScreenY)
+	return clampScreen(x), clampScreen(y)
+}
+
+// clampScreen 射影後の座標を制限
+func clampScreen(v int) int {
+	if v < -screenLimit {
+		return -screenLimit
+	}
+	if v > screenLimit {
+		return screenLimit
+	}
+	return v
+}
+
+// Flush 溜めた三角形を奥から順にバックバッファへ描画
+func (r *Renderer) Flush() {
+	// 奥行きの降順に挿入ソート（安定なので、同じ奥行きの三角形は追加した順に描く）
+	for i := 1; i < r.count; i++ {
+		key := r.order[i]
+		depth := r.tris[key].depth
+		j := i - 1
+		for j >= 0 && r.tris[r.order[j]].depth < depth {
+			r.order[j+1] = r.order[j]
+			j--
+		}
+		r.order[j+1] = key
+	}
+
+	for i := 0; i < r.count; i++ {
+		t := &r.tris[r.order[i]]
+		graphics.FillTriangleMode4(int(t.x0), int(t.y0), int(t.x1), int(t.y1), int(t.x2), int(t.y2), t.color)
+	}
+	r.Stats.Drawn = r.count
+	r.count = 0
+}
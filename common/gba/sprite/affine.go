@@ -0,0 +1,136 @@
+package sprite
+
+import "github.com/ryomak/gameboys/common/math"
+
+// アフィンスプライト（回転・拡大縮小）
+// 行列は32個あり、シャドウOAMの4エントリごとの4つ目の値に pa, pb, pc, pd の順で入る
+
+// MaxAffine アフィン行列の数
+const MaxAffine = 32
+
+// maxInverseScale 行列の成分（8.8固定小数点、符号付き16bit）に収まる拡大率の逆数の上限
+const maxInverseScale = 127
+
+// Affine 確保したアフィン行列の番号
+type Affine uint8
+
+// AllocAffine 空いているアフィン行列を確保（等倍・回転なしで初期化）
+func (m *Manager) AllocAffine() (Affine, bool) {
+	for i := 0; i < MaxAffine; i++ {
+		if m.affineUsed&(1<<i) == 0 {
+			m.affineUsed |= 1 << i
+			m.SetAffine(Affine(i), 0, math.FixedOne, math.FixedOne)
+			return Affine(i), true
+		}
+	}
+	return 0, false
+}
+
+// FreeAffine アフィン行列を解放
+func (m *Manager) FreeAffine(a Affine) {
+	m.affineUsed &^= 1 << (a % MaxAffine)
+}
+
+// SetAffineMatrix 行列の成分を直接設定（8.8固定小数点、画面の1ピクセルあたりのタイル上の移動量）
+func (m *Manager) SetAffineMatrix(a Affine, pa, pb, pc, pd int16) {
+	base := int(a%MaxAffine) * 4
+	m.shadow[base].affine = uint16(pa)
+	m.shadow[base+1].affine = uint16(pb)
+	m.shadow[base+2].affine = uint16(pc)
+	m.shadow[base+3].affine = uint16(pd)
+	m.dirty = true
+}
+
+// SetAffine 回転と拡大率から行列を設定
+// angle: 0-255 が 0-360度（反時計回り）、scaleX/scaleY: 表示される大きさの倍率
+func (m *Manager) SetAffine(a Affine, angle int32, scaleX, scaleY math.Fixed) {
+	// 行列は画面からタイルへの変換なので、回転は逆向き、拡大率は逆数になる
+	cos := math.Cos(angle)
+	sin := math.Sin(angle)
+	ix := inverseScale(scaleX)
+	iy := inverseScale(scaleY)
+	m.SetAffineMatrix(a,
+		toAffine(cos.Mul(ix)),
+		toAffine(sin.Mul(ix).Neg()),
+		toAffine(sin.Mul(iy)),
+		toAffine(cos.Mul(iy)),
+	)
+}
+
+// inverseScale 拡大率の逆数（行列に収まるよう制限）
+func inverseScale(scale math.Fixed) math.Fixed {
+	limit := math.NewFixed(maxInverseScale)
+	if scale <= 0 {
+		return limit
+	}
+	inv := math.FixedOne.Div(scale)
+	if inv > limit {
+		return limit
+	}
+	return inv
+}
+
+// toAffine 16.16固定小数点を8.8固定小数点に変換
+func toAffine(v math.Fixed) int16 {
+	return int16(v >> 8)
+}
+
+// NeedsDoubleSize 回転・拡大した絵が元の大きさの枠からはみ出すか
+// はみ出す場合は2倍の描画範囲（double-size）にしないと端が切れる
+func NeedsDoubleSize(angle int32, scaleX, scaleY math.Fixed) bool {
+	extent := math.Cos(angle).Abs().Add(math.Sin(angle).Abs())
+	return extent.Mul(scaleX.Max(scaleY)) > math.FixedOne
+}
+
+// SetAffine アフィン行列を使う設定にする
+// double: 描画範囲を2倍にする（位置はSetCenterで合わせる）
+func (a *Attributes) SetAffine(index Affine, double bool) {
+	a.Attr1 = a.Attr1&^attr1AffineIndex | uint16(index%MaxAffine)<<9
+	a.Attr0 = a.Attr0&^(attr0Affine|attr0Double) | attr0Affine
+	if double {
+		a.Attr0 |= attr0Double
+	}
+}
+
+// ClearAffine 通常スプライトに戻す（表示される）
+func (a *Attributes) ClearAffine() {
+	if !a.IsAffine() {
+		return
+	}
+	a.Attr1 &^= attr1AffineIndex
+	a.Attr0 &^= attr0Affine | attr0Double
+}
+
+// IsDoubleSize 2倍の描画範囲を使っているか
+func (a *Attributes) IsDoubleSize() bool {
+	return a.IsAffine() && a.Attr0&attr0Double != 0
+}
+
+// SetCenter 中心の位置を設定（double-sizeなら描画範囲の広がりも考慮する）
+func (a *Attributes) SetCenter(x, y int) {
+	w, h := a.Size().Dimensions()
+	if a.IsDoubleSize() {
+		a.SetPosition(x-w, y-h)
+		return
+	}
+	a.SetPosition(x-w/2, y-h/2)
+}
+
+// SetRotScale スプライトを回転・拡大縮小して表示する
+// 行列を設定し、必要なら自動でdouble-sizeにする（位置はSetCenterで合わせる）
+func (m *Manager) SetRotScale(h Handle, aff Affine, angle int32, scale math.Fixed) {
+	m.SetAffine(aff, angle, scale, scale)
+	m.Sprite(h).SetAffine(aff, NeedsDoubleSize(angle, scale, scale))
+}
+
+// PlaceProjected 射影結果の位置・スケールでスプライトを表示する（画面外なら非表示）
+// baseScale: 射影のスケールが1のときの表示倍率
+func (m *Manager) PlaceProjected(h Handle, aff Affine, result math.ProjectionResult, angle int32, baseScale math.Fixed) {
+	s := m.Sprite(h)
+	if !result.Visible {
+		s.Hide()
+		return
+	}
+	m.SetRotScale(h, aff, angle, result.Scale.Mul(baseScale))
+	s.SetCenter(int(result.ScreenX), int(result.ScreenY))
+}
@@ -0,0 +1,194 @@
+package sprite
+
+// OBJタイル（1次元マッピング）の割り当て
+// 名前を付けてタイルデータを読み込み、同じ名前の読み込みは参照カウントを増やして共有する
+
+// MaxTileBlocks 同時に読み込めるタイルデータの数
+const MaxTileBlocks = 64
+
+// tileBlock 読み込んだタイルデータ
+type tileBlock struct {
+	name  string
+	start int      // 先頭のタイル番号
+	size  int      // タイル番号の数（偶数に切り上げる）
+	refs  int      // 参照カウント
+	data  []uint16 // 元のデータ（詰め直しで読み込み直す）
+}
+
+// TileAllocator OBJタイルの割り当て
+// ブロックは先頭のタイル番号の順に並べて保持する
+type TileAllocator struct {
+	// OnMove 詰め直しでタイルが移動したときに呼ばれる（スプライトのタイル番号を直すのに使う）
+	OnMove func(name string, from, size, to int)
+
+	start, end int // 使えるタイル番号の範囲（endは含まない）
+	blocks     [MaxTileBlocks]tileBlock
+	count      int
+}
+
+// NewTileAllocator 使えるタイル番号の範囲を指定して作成
+// タイルモードは0-MaxTiles、Mode 3-5はBitmapTileStart-MaxTiles
+func NewTileAllocator(start, end int) *TileAllocator {
+	if start < 0 {
+		start = 0
+	}
+	if end > MaxTiles {
+		end = MaxTiles
+	}
+	// 8bppのタイルは偶数番号から始める必要があるため、範囲も偶数にそろえる
+	start = (start + 1) &^ 1
+	return &TileAllocator{start: start, end: end}
+}
+
+// tilesFor データに必要なタイル番号の数（偶数に切り上げ）
+func tilesFor(data []uint16) int {
+	n := (len(data)*2 + TileBytes - 1) / TileBytes
+	return (n + 1) &^ 1
+}
+
+// find 名前のブロックの位置（なければ-1）
+func (a *TileAllocator) find(name string) int {
+	for i := 0; i < a.count; i++ {
+		if a.blocks[i].name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Load タイルデータを名前を付けて読み込み、先頭のタイル番号を返す
+// 同じ名前が読み込み済みなら、読み込まずに参照カウントを増やす
+// 空きが足りない場合は詰め直してから探し、それでも足りなければfalseを返す
+// dataは詰め直しで読み込み直すため、書き換えずに残しておく
+func (a *TileAllocator) Load(name string, data []uint16) (int, bool) {
+	if i := a.find(name); i >= 0 {
+		a.blocks[i].refs++
+		return a.blocks[i].start, true
+	}
+	if a.count >= MaxTileBlocks || len(data) == 0 {
+		return 0, false
+	}
+
+	size := tilesFor(data)
+	index, start, ok := a.findGap(size)
+	if !ok {
+		if a.Free() < size {
+			return 0, false
+		}
+		a.Defragment()
+		index, start, ok = a.findGap(size)
+		if !ok {
+			return 0, false
+		}
+	}
+
+	// 並び順を保って挿入
+	copy(a.blocks[index+1:a.count+1], a.blocks[index:a.count])
+	a.blocks[index] = tileBlock{name: name, start: start, size: size, refs: 1, data: data}
+	a.count++
+	LoadTiles(start, data)
+	return start, true
+}
+
+// findGap size個の連続した空きを先頭から探す（挿入位置と先頭のタイル番号を返す）
+func (a *TileAllocator) findGap(size int) (index, start int, ok bool) {
+	pos := a.start
+	for i := 0; i < a.count; i++ {
+		if a.blocks[i].start-pos >= size {
+			return i, pos, true
+		}
+		pos = a.blocks[i].start + a.blocks[i].size
+	}
+	if a.end-pos >= size {
+		return a.count, pos, true
+	}
+	return 0, 0, false
+}
+
+// Lookup 読み込み済みの名前の先頭タイル番号
+func (a *TileAllocator) Lookup(name string) (int, bool) {
+	if i := a.find(name); i >= 0 {
+		return a.blocks[i].start, true
+	}
+	return 0, false
+}
+
+// Release 参照カウントを減らし、0になったら解放する
+func (a *TileAllocator) Release(name string) {
+	i := a.find(name)
+	if i < 0 {
+		return
+	}
+	a.blocks[i].refs--
+	if a.blocks[i].refs > 0 {
+		return
+	}
+	copy(a.blocks[i:a.count-1], a.blocks[i+1:a.count])
+	a.count--
+	a.blocks[a.count] = tileBlock{}
+}
+
+// Defragment ブロックを前に詰めて空きを1つにまとめ、移動したブロックの数を返す
+// 移動したブロックはVRAMに読み込み直し、OnMoveを呼ぶ（VBlank中に呼ぶ）
+func (a *TileAllocator) Defragment() int {
+	moved := 0
+	pos := a.start
+	for i := 0; i < a.count; i++ {
+		b := &a.blocks[i]
+		if b.start != pos {
+			from := b.start
+			b.start = pos
+			LoadTiles(pos, b.data)
+			if a.OnMove != nil {
+				a.OnMove(b.name, from, b.size, pos)
+			}
+			moved++
+		}
+		pos += b.size
+	}
+	return moved
+}
+
+// Free 空いているタイル番号の合計
+func (a *TileAllocator) Free() int {
+	used := 0
+	for i := 0; i < a.count; i++ {
+		used += a.blocks[i].size
+	}
+	return a.end - a.start - used
+}
+
+// LargestFree 連続して空いているタイル番号の最大数（詰め直さずに読み込める大きさ）
+func (a *TileAllocator) LargestFree() int {
+	largest := 0
+	pos := a.start
+	for i := 0; i < a.count; i++ {
+		largest = max(largest, a.blocks[i].start-pos)
+		pos = a.blocks[i].start + a.blocks[i].size
+	}
+	return max(largest, a.end-pos)
+}
+
+// MoveTiles fromからsize個のタイルを使っているスプライトをtoへ付け替える（TileAllocator.OnMoveで使う）
+func (m *Manager) MoveTiles(from, size, to int) {
+	for i := range m.shadow {
+		s := &m.shadow[i]
+		if !m.isUsed(i) {
+			continue
+		}
+		if tile := s.Tile(); tile >= from && tile < from+size {
+			s.SetTile(tile - from + to)
+			m.dirty = true
+		}
+	}
+}
+
+// MoveTiles fromからsize個のタイルを先頭にしている再生中のクリップをtoへ付け替える
+func (p *Player) MoveTiles(from, size, to int) {
+	for i := range p.slots {
+		s := &p.slots[i]
+		if base := int(s.base); s.clip != nil && base >= from && base < from+size {
+			s.base = uint16(base - from + to)
+		}
+	}
+}
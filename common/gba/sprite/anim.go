@@ -0,0 +1,170 @@
+package sprite
+
+// スプライトのアニメーション
+// クリップ（タイルとフレーム数の並び）をデータとして用意し、Playerがスプライトごとに再生する
+
+// PlayMode クリップの再生方法
+type PlayMode uint8
+
+const (
+	Loop     PlayMode = iota // 最後まで進んだら最初に戻る
+	PingPong                 // 最後まで進んだら逆向きに戻る
+	Once                     // 最後のフレームで止まる
+)
+
+// フレームごとの反転
+const (
+	FrameFlipH = 1 << 0
+	FrameFlipV = 1 << 1
+)
+
+// Frame アニメーションの1コマ
+type Frame struct {
+	Tile     uint16 // Playで指定した先頭タイルからの番号
+	Duration uint8  // 表示するフレーム数（0は1として扱う）
+	Flags    uint8  // FrameFlipH、FrameFlipV
+}
+
+// Clip アニメーションのクリップ
+type Clip struct {
+	Frames []Frame
+	Mode   PlayMode
+}
+
+// animState スプライト1つ分の再生状態
+type animState struct {
+	clip     *Clip
+	base     uint16 // 先頭タイル
+	frame    uint8  // 現在のコマ
+	timer    uint8  // 現在のコマを表示したフレーム数
+	reverse  bool   // PingPongで逆向きに進んでいるか
+	active   bool
+	onFinish func(h Handle)
+}
+
+// Player スプライトごとのクリップの再生
+type Player struct {
+	sprites *Manager
+	slots   [MaxSprites]animState
+}
+
+// NewPlayer 再生を管理するPlayerを作成（初期化時に使う）
+func NewPlayer(sprites *Manager) *Player {
+	return &Player{sprites: sprites}
+}
+
+// Play スプライトでクリップを再生
+// 同じクリップを再生中なら続きから再生し、別のクリップなら最初から再生する
+// baseTile: クリップのタイル番号に足す先頭タイル
+func (p *Player) Play(h Handle, clip *Clip, baseTile int) {
+	s := &p.slots[h]
+	if s.active && s.clip == clip && s.base == uint16(baseTile) {
+		return
+	}
+	p.Restart(h, clip, baseTile)
+}
+
+// Restart クリップを最初から再生
+func (p *Player) Restart(h Handle, clip *Clip, baseTile int) {
+	s := &p.slots[h]
+	onFinish := s.onFinish
+	*s = animState{clip: clip, base: uint16(baseTile), onFinish: onFinish}
+	if clip == nil || len(clip.Frames) == 0 {
+		return
+	}
+	s.active = true
+	p.apply(h)
+}
+
+// Stop 再生を止める（現在のコマのまま）
+func (p *Player) Stop(h Handle) {
+	p.slots[h].active = false
+}
+
+// IsPlaying 再生中か（Onceで最後まで進んだらfalse）
+func (p *Player) IsPlaying(h Handle) bool {
+	return p.slots[h].active
+}
+
+// Frame 現在のコマの番号
+func (p *Player) Frame(h Handle) int {
+	return int(p.slots[h].frame)
+}
+
+// OnFinish Onceのクリップが最後まで進んだときに呼ぶ関数を設定（nilで解除）
+// クリップを切り替えても設定は残る
+func (p *Player) OnFinish(h Handle, fn func(h Handle)) {
+	p.slots[h].onFinish = fn
+}
+
+// Update 再生中のクリップをすべて1フレーム進める（毎フレーム1回呼ぶ）
+func (p *Player) Update() {
+	for i := range p.slots {
+		s := &p.slots[i]
+		if !s.active {
+			continue
+		}
+		s.timer++
+		duration := s.clip.Frames[s.frame].Duration
+		if duration == 0 {
+			duration = 1
+		}
+		if s.timer < duration {
+			continue
+		}
+		s.timer = 0
+		if p.advance(s) {
+			p.apply(Handle(i))
+			continue
+		}
+
+		// Onceで最後のコマまで表示し終えた
+		s.active = false
+		if s.onFinish != nil {
+			s.onFinish(Handle(i))
+		}
+	}
+}
+
+// advance 次のコマに進める（Onceで最後まで進んでいたらfalse）
+func (p *Player) advance(s *animState) bool {
+	last := uint8(len(s.clip.Frames) - 1)
+	switch s.clip.Mode {
+	case Loop:
+		if s.frame >= last {
+			s.frame = 0
+		} else {
+			s.frame++
+		}
+	case PingPong:
+		if last == 0 {
+			return true
+		}
+		if s.reverse {
+			s.frame--
+			if s.frame == 0 {
+				s.reverse = false
+			}
+		} else {
+			s.frame++
+			if s.frame >= last {
+				s.reverse = true
+			}
+		}
+	default:
+		if s.frame >= last {
+			return false
+		}
+		s.frame++
+	}
+	return true
+}
+
+// apply 現在のコマをスプライトの属性に反映
+func (p *Player) apply(h Handle) {
+	s := &p.slots[h]
+	f := s.clip.Frames[s.frame]
+	a := p.sprites.Sprite(h)
+	a.SetTile(int(s.base) + int(f.Tile))
+	a.SetFlip(f.Flags&FrameFlipH != 0, f.Flags&FrameFlipV != 0)
+}
@@ -0,0 +1,304 @@
+package sprite
+
+import (
+	"unsafe"
+
+	"github.com/ryomak/gameboys/common/gba/interrupt"
+)
+
+// スプライトの多重化（128個を超えるスプライトの表示）
+// 論理スプライトをYでソートし、OAMのエントリ（スロット）を走査線が通り過ぎたものから使い回す
+// 書き換えはVCount割り込みで、ゾーン（MuxZoneHeightライン）ごとにまとめて行う
+//
+// 使い方:
+//  1. 初期化時にManager.AllocAtでスロットを確保し、interrupt.Set(interrupt.VCount, mux.HandleVCount)
+//  2. 毎フレーム Clear、Add、Build（VBlank前に済ませる）
+//  3. VBlank中に Manager.Commit の後で Commit
+//
+// スロット同士の前後関係はフレームごとに変わるため、重なっても気にならないもの（弾など）に使う
+
+const (
+	MaxMuxSprites = 512 // 1フレームに追加できる論理スプライトの最大数
+	MuxZoneHeight = 8   // 書き換えをまとめるライン数
+	muxZones      = 160 / MuxZoneHeight
+	muxLead       = 2 // ゾーンの何ライン前に書き換えるか（描画中のラインのOAMを避ける）
+
+	// LineCycles 1ラインでスプライトの描画に使えるサイクル数（HBlank中のOAMアクセスを許可していない場合）
+	LineCycles = 1210
+)
+
+// MuxStats 直前にBuildしたフレームの統計
+type MuxStats struct {
+	Sprites     int // 表示する論理スプライト
+	Culled      int // 非表示・画面外で除いたもの
+	Dropped     int // 追加できる数を超えて捨てたもの
+	LineOverrun int // 走査線の描画サイクルが足りずに表示しなかったもの
+	SlotOverrun int // 空いているスロットがなく表示しなかったもの
+	PeakLine    int // 描画サイクルが最も多いライン
+	PeakCycles  int // そのラインの描画サイクル
+}
+
+// Overloaded 走査線あたりの上限を超えて、表示できなかったスプライトがあるか
+func (s *MuxStats) Overloaded() bool {
+	return s.LineOverrun > 0 || s.SlotOverrun > 0
+}
+
+// muxWrite スロットへの書き込み
+type muxWrite struct {
+	attr0, attr1, attr2 uint16
+	slot                uint8
+}
+
+// muxSchedule 1フレーム分の書き込み（ゾーンの順）
+type muxSchedule struct {
+	writes  [MaxMuxSprites]muxWrite
+	zoneEnd [muxZones]uint16 // ゾーンごとの書き込みの終わり
+}
+
+// Multiplexer スプライトの多重化
+type Multiplexer struct {
+	Stats MuxStats
+
+	// LineBudget 1ラインに使ってよい描画サイクル（超える分は表示しない）
+	LineBudget int
+
+	first, slots int
+
+	sprites [MaxMuxSprites]Attributes
+	count   int
+	dropped int
+	frame   int // 上限を超えたときに落とすスプライトをフレームごとに入れ替える
+
+	order  [MaxMuxSprites]uint16
+	bucket [160 + 1]uint16
+	cycles [160]uint16
+	freeAt [MaxSprites]int16 // スロットが空くライン（使っているスプライトの下端の次）
+
+	// Buildで書く側と、割り込みで読む側を分ける
+	schedules [2]muxSchedule
+	back      int
+	zone      int // 次に書き換えるゾーン（読む側）
+}
+
+// NewMultiplexer OAMのfirstからslots個のエントリを使う多重化を作成
+// 使うエントリはManager.AllocAtで確保しておく（Manager.Commitで上書きされないよう非表示のままにする）
+// 作業バッファが大きいため、IWRAMではなくヒープ（EWRAM）に確保する
+func NewMultiplexer(first, slots int) *Multiplexer {
+	if first < 0 {
+		first = 0
+	}
+	if first+slots > MaxSprites {
+		slots = MaxSprites - first
+	}
+	return &Multiplexer{LineBudget: LineCycles, first: first, slots: slots}
+}
+
+// Clear フレームの論理スプライトを空にする
+func (x *Multiplexer) Clear() {
+	x.count = 0
+	x.dropped = 0
+}
+
+// Add 論理スプライトを追加（一杯ならfalse）
+// 属性はManager.Spriteと同じように設定したものを渡す（アフィン行列はManagerで確保する）
+func (x *Multiplexer) Add(a Attributes) bool {
+	if x.count >= MaxMuxSprites {
+		x.dropped++
+		return false
+	}
+	x.sprites[x.count] = a
+	x.count++
+	return true
+}
+
+// Build 論理スプライトをYでソートし、スロットと書き換えるゾーンを決める（VBlank前に呼ぶ）
+// 走査線の描画サイクルかスロットが足りない場合はそのスプライトを表示せず、
+// 落とすスプライトをフレームごとに入れ替える（ちらつくがすべてが見える）
+func (x *Multiplexer) Build() {
+	x.Stats = MuxStats{Dropped: x.dropped}
+	x.frame++
+	visible := x.sortByY()
+
+	for i := range x.cycles {
+		x.cycles[i] = 0
+	}
+	for i := 0; i < x.slots; i++ {
+		x.freeAt[i] = -1 << 14
+	}
+
+	sch := &x.schedules[x.back]
+	n := 0
+	zone := 0
+	cursor := 0
+	for _, index := range x.order[:visible] {
+		a := &x.sprites[index]
+		top, bottom, cost := spriteExtent(a)
+
+		// このスプライトを書き換えるゾーン（上端が入るゾーン）まで進める
+		for zone < muxZones-1 && max(top, 0) >= (zone+1)*MuxZoneHeight {
+			sch.zoneEnd[zone] = uint16(n)
+			zone++
+		}
+
+		if !x.fitsLines(top, bottom, cost) {
+			x.Stats.LineOverrun++
+			continue
+		}
+
+		// 書き換えるときまでに空いているスロットを探す
+		writeLine := zone*MuxZoneHeight - muxLead
+		slot := -1
+		for j := 0; j < x.slots; j++ {
+			s := (cursor + j) % x.slots
+			if int(x.freeAt[s]) <= writeLine {
+				slot = s
+				break
+			}
+		}
+		if slot < 0 {
+			x.Stats.SlotOverrun++
+			continue
+		}
+		cursor = slot + 1
+
+		x.freeAt[slot] = int16(bottom)
+		x.addCycles(top, bottom, cost)
+		sch.writes[n] = muxWrite{attr0: a.Attr0, attr1: a.Attr1, attr2: a.Attr2, slot: uint8(x.first + slot)}
+		n++
+	}
+	for ; zone < muxZones; zone++ {
+		sch.zoneEnd[zone] = uint16(n)
+	}
+	x.Stats.Sprites = n
+}
+
+// sortByY 表示する論理スプライトを上端のYで並べ、その数を返す（計数ソート）
+// 同じラインのスプライトは、追加した順をフレームごとにずらして並べる
+func (x *Multiplexer) sortByY() int {
+	for i := range x.bucket {
+		x.bucket[i] = 0
+	}
+	visible := 0
+	for i := 0; i < x.count; i++ {
+		top, bottom, _ := spriteExtent(&x.sprites[i])
+		if x.sprites[i].IsHidden() || bottom <= 0 || top >= 160 || !onScreenX(&x.sprites[i]) {
+			x.Stats.Culled++
+			continue
+		}
+		x.bucket[max(top, 0)+1]++
+		visible++
+	}
+	for i := 1; i < len(x.bucket); i++ {
+		x.bucket[i] += x.bucket[i-1]
+	}
+
+	start := 0
+	if x.count > 0 {
+		start = x.frame % x.count
+	}
+	for k := 0; k < x.count; k++ {
+		i := (start + k) % x.count
+		a := &x.sprites[i]
+		top, bottom, _ := spriteExtent(a)
+		if a.IsHidden() || bottom <= 0 || top >= 160 || !onScreenX(a) {
+			continue
+		}
+		key := max(top, 0)
+		x.order[x.bucket[key]] = uint16(i)
+		x.bucket[key]++
+	}
+	return visible
+}
+
+// fitsLines 上端から下端までのすべてのラインに描画サイクルの余裕があるか
+func (x *Multiplexer) fitsLines(top, bottom, cost int) bool {
+	for line := max(top, 0); line < min(bottom, 160); line++ {
+		if int(x.cycles[line])+cost > x.LineBudget {
+			return false
+		}
+	}
+	return true
+}
+
+// addCycles 上端から下端までのラインに描画サイクルを足す
+func (x *Multiplexer) addCycles(top, bottom, cost int) {
+	for line := max(top, 0); line < min(bottom, 160); line++ {
+		x.cycles[line] += uint16(cost)
+		if int(x.cycles[line]) > x.Stats.PeakCycles {
+			x.Stats.PeakCycles = int(x.cycles[line])
+			x.Stats.PeakLine = line
+		}
+	}
+}
+
+// spriteExtent 表示される上端・下端（下端は含まない）と、1ラインの描画サイクル
+// 通常スプライトは幅と同じ、アフィンスプライトは描画範囲の幅の2倍+10サイクル
+func spriteExtent(a *Attributes) (top, bottom, cost int) {
+	w, h := a.Size().Dimensions()
+	_, top = a.Position()
+	cost = w
+	if a.IsAffine() {
+		if a.IsDoubleSize() {
+			w *= 2
+			h *= 2
+		}
+		cost = w*2 + 10
+	}
+	return top, top + h, cost
+}
+
+// onScreenX 横方向に画面にかかっているか
+func onScreenX(a *Attributes) bool {
+	w, _ := a.Size().Dimensions()
+	if a.IsDoubleSize() {
+		w *= 2
+	}
+	x, _ := a.Position()
+	return x < 240 && x+w > 0
+}
+
+// Commit Buildした内容に切り替え、最初のゾーンを書き込む（VBlank中、Manager.Commitの後に呼ぶ）
+func (x *Multiplexer) Commit() {
+	front := x.back
+	x.back ^= 1
+
+	// 前のフレームのスプライトが残らないよう、すべてのスロットを非表示にする
+	oam := (*[MaxSprites]Attributes)(unsafe.Pointer(uintptr(RegOAM)))
+	for i := x.first; i < x.first+x.slots; i++ {
+		oam[i].Attr0 = attr0Disable
+	}
+
+	x.zone = 0
+	x.writeZone(&x.schedules[front])
+}
+
+// HandleVCount 次のゾーンのスロットを書き換える（VCount割り込みで呼ばれる）
+func (x *Multiplexer) HandleVCount() {
+	x.writeZone(&x.schedules[x.back^1])
+}
+
+// writeZone 現在のゾーンを書き込み、書き込みのある次のゾーンで割り込みを起こす
+func (x *Multiplexer) writeZone(sch *muxSchedule) {
+	oam := (*[MaxSprites]Attributes)(unsafe.Pointer(uintptr(RegOAM)))
+	start := uint16(0)
+	if x.zone > 0 {
+		start = sch.zoneEnd[x.zone-1]
+	}
+	for _, w := range sch.writes[start:sch.zoneEnd[x.zone]] {
+		o := &oam[w.slot]
+		o.Attr0 = w.attr0
+		o.Attr1 = w.attr1
+		o.Attr2 = w.attr2
+	}
+
+	// 書き込みのない後ろのゾーンは飛ばす
+	x.zone++
+	for x.zone < muxZones && sch.zoneEnd[x.zone] == sch.zoneEnd[x.zone-1] {
+		x.zone++
+	}
+	if x.zone >= muxZones {
+		interrupt.SetVCountLine(-1)
+		return
+	}
+	interrupt.SetVCountLine(x.zone*MuxZoneHeight - muxLead)
+}
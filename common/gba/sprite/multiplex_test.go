@@ -0,0 +1,230 @@
+package sprite
+
+import "testing"
+
+// muxSprite (x, y)に置いた表示中のスプライト（tileで見分ける）
+func muxSprite(x, y int, size Size, tile int) Attributes {
+	var a Attributes
+	a.SetSize(size)
+	a.SetPosition(x, y)
+	a.SetTile(tile)
+	return a
+}
+
+// muxAffine アフィンスプライト（double-sizeなし）
+func muxAffine(x, y int, size Size, tile int) Attributes {
+	a := muxSprite(x, y, size, tile)
+	a.SetAffine(0, false)
+	return a
+}
+
+// built 直前のBuildで決まった書き込み（ゾーンの順）
+func built(x *Multiplexer) []muxWrite {
+	return x.schedules[x.back].writes[:x.Stats.Sprites]
+}
+
+// buildMux firstからslots個のスロットで、spritesを1フレーム分Buildする
+func buildMux(first, slots int, sprites ...Attributes) *Multiplexer {
+	x := NewMultiplexer(first, slots)
+	for _, a := range sprites {
+		x.Add(a)
+	}
+	x.Build()
+	return x
+}
+
+func TestMultiplexerStats(t *testing.T) {
+	row := func(n int, size Size, affine bool) []Attributes {
+		sprites := make([]Attributes, n)
+		for i := range sprites {
+			if affine {
+				sprites[i] = muxAffine(0, 0, size, i)
+			} else {
+				sprites[i] = muxSprite(0, 0, size, i)
+			}
+		}
+		return sprites
+	}
+	hidden := muxSprite(0, 0, Size8x8, 0)
+	hidden.Hide()
+
+	tests := []struct {
+		name    string
+		slots   int
+		sprites []Attributes
+		want    MuxStats
+	}{
+		{
+			name:    "別々のライン",
+			slots:   4,
+			sprites: []Attributes{muxSprite(0, 0, Size8x8, 0), muxSprite(0, 40, Size8x8, 1), muxSprite(0, 80, Size8x8, 2)},
+			want:    MuxStats{Sprites: 3, PeakCycles: 8},
+		},
+		{
+			name:    "同じラインでスロットが足りない",
+			slots:   2,
+			sprites: row(3, Size8x8, false),
+			want:    MuxStats{Sprites: 2, SlotOverrun: 1, PeakCycles: 16},
+		},
+		{
+			name:    "64x64が19個並ぶと描画サイクルが足りない",
+			slots:   32,
+			sprites: row(19, Size64x64, false),
+			want:    MuxStats{Sprites: 18, LineOverrun: 1, PeakCycles: 18 * 64},
+		},
+		{
+			name:    "アフィンは幅の2倍+10サイクル",
+			slots:   32,
+			sprites: row(17, Size32x32, true),
+			want:    MuxStats{Sprites: 16, LineOverrun: 1, PeakCycles: 16 * (32*2 + 10)},
+		},
+		{
+			name:    "非表示・画面外は除く",
+			slots:   4,
+			sprites: []Attributes{hidden, muxSprite(0, 170, Size8x8, 1), muxSprite(-20, 0, Size8x8, 2), muxSprite(240, 0, Size8x8, 3), muxSprite(-4, 0, Size8x8, 4)},
+			want:    MuxStats{Sprites: 1, Culled: 4, PeakCycles: 8},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x := buildMux(0, tt.slots, tt.sprites...)
+			if x.Stats != tt.want {
+				t.Errorf("Stats = %+v, want %+v", x.Stats, tt.want)
+			}
+			if x.Stats.Overloaded() != (tt.want.LineOverrun > 0 || tt.want.SlotOverrun > 0) {
+				t.Errorf("Overloaded() = %v", x.Stats.Overloaded())
+			}
+		})
+	}
+}
+
+func TestMultiplexerSlots(t *testing.T) {
+	tests := []struct {
+		name        string
+		slots       int
+		sprites     []Attributes
+		wantSlots   []uint8 // 書き込む順（ゾーンの順）のスロット
+		wantOverrun int
+	}{
+		{
+			name:  "通り過ぎたスロットを使い回す",
+			slots: 2,
+			sprites: []Attributes{
+				muxSprite(0, 0, Size8x8, 0), muxSprite(8, 0, Size8x8, 1),
+				muxSprite(0, 16, Size8x8, 2), muxSprite(8, 16, Size8x8, 3),
+			},
+			wantSlots: []uint8{10, 11, 10, 11},
+		},
+		{
+			name:  "書き換えのラインがまだ描画中なら使えない",
+			slots: 1,
+			sprites: []Attributes{
+				muxSprite(0, 0, Size8x8, 0),
+				muxSprite(0, 8, Size8x8, 1), // ゾーン1の書き換えは6ライン目で、前のスプライトは7ライン目まで
+			},
+			wantSlots:   []uint8{10},
+			wantOverrun: 1,
+		},
+		{
+			name:  "muxLeadの余裕があれば使い回す",
+			slots: 1,
+			sprites: []Attributes{
+				muxSprite(0, 0, Size8x8, 0),
+				muxSprite(0, 16, Size8x8, 1), // ゾーン2の書き換えは14ライン目
+			},
+			wantSlots: []uint8{10, 10},
+		},
+		{
+			name:  "Yの順に並べる",
+			slots: 3,
+			sprites: []Attributes{
+				muxSprite(0, 100, Size8x8, 0), muxSprite(0, 20, Size8x8, 1), muxSprite(0, 60, Size8x8, 2),
+			},
+			wantSlots: []uint8{10, 11, 12},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x := buildMux(10, tt.slots, tt.sprites...)
+			writes := built(x)
+			if len(writes) != len(tt.wantSlots) {
+				t.Fatalf("%d writes, want %d", len(writes), len(tt.wantSlots))
+			}
+			for i, w := range writes {
+				if w.slot != tt.wantSlots[i] {
+					t.Errorf("write %d: slot %d, want %d", i, w.slot, tt.wantSlots[i])
+				}
+			}
+			if x.Stats.SlotOverrun != tt.wantOverrun {
+				t.Errorf("SlotOverrun = %d, want %d", x.Stats.SlotOverrun, tt.wantOverrun)
+			}
+		})
+	}
+}
+
+func TestMultiplexerZones(t *testing.T) {
+	x := buildMux(0, 4, muxSprite(0, 100, Size8x8, 0), muxSprite(0, 3, Size8x8, 1), muxSprite(0, 50, Size8x8, 2))
+	writes := built(x)
+
+	// 上端が入るゾーンで書き換える（Yの順に並ぶ）
+	wantTiles := []int{1, 2, 0}
+	for i, w := range writes {
+		a := Attributes{Attr0: w.attr0, Attr1: w.attr1, Attr2: w.attr2}
+		if a.Tile() != wantTiles[i] {
+			t.Errorf("write %d: tile %d, want %d", i, a.Tile(), wantTiles[i])
+		}
+	}
+	sch := &x.schedules[x.back]
+	wantEnd := map[int]uint16{0: 1, 5: 1, 6: 2, 11: 2, 12: 3, muxZones - 1: 3}
+	for zone, end := range wantEnd {
+		if sch.zoneEnd[zone] != end {
+			t.Errorf("zoneEnd[%d] = %d, want %d", zone, sch.zoneEnd[zone], end)
+		}
+	}
+}
+
+func TestMultiplexerRotatesDropped(t *testing.T) {
+	// 同じラインに3個、スロットは2個なので毎フレーム1個を落とす
+	x := NewMultiplexer(0, 2)
+	for i := 0; i < 3; i++ {
+		x.Add(muxSprite(i*16, 0, Size8x8, i))
+	}
+
+	dropped := map[int]bool{}
+	for frame := 0; frame < 3; frame++ {
+		x.Build()
+		if x.Stats.SlotOverrun != 1 {
+			t.Fatalf("frame %d: SlotOverrun = %d, want 1", frame, x.Stats.SlotOverrun)
+		}
+		shown := map[int]bool{}
+		for _, w := range built(x) {
+			shown[int(w.attr2&attr2Tile)] = true
+		}
+		for tile := 0; tile < 3; tile++ {
+			if !shown[tile] {
+				dropped[tile] = true
+			}
+		}
+	}
+	if len(dropped) != 3 {
+		t.Errorf("dropped sprites %v, want every sprite to be dropped once", dropped)
+	}
+}
+
+func TestMultiplexerAddLimit(t *testing.T) {
+	x := NewMultiplexer(0, 4)
+	for i := 0; i < MaxMuxSprites; i++ {
+		if !x.Add(muxSprite(0, 0, Size8x8, 0)) {
+			t.Fatalf("Add %d failed", i)
+		}
+	}
+	if x.Add(muxSprite(0, 0, Size8x8, 0)) {
+		t.Error("Add should fail when full")
+	}
+	x.Build()
+	if x.Stats.Dropped != 1 {
+		t.Errorf("Dropped = %d, want 1", x.Stats.Dropped)
+	}
+}
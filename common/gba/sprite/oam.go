@@ -0,0 +1,115 @@
+package sprite
+
+import (
+	"unsafe"
+
+	"github.com/ryomak/gameboys/common/gba/memory"
+)
+
+// OAMのアドレスとエントリ数
+const (
+	RegOAM     = 0x07000000
+	MaxSprites = 128
+)
+
+// Handle 確保したスプライトの番号（OAMのエントリ番号）
+type Handle uint8
+
+// Manager スプライト管理
+// RAM上のシャドウOAMを編集し、VBlank中にCommitでOAMへ1回のDMAで転送する
+type Manager struct {
+	shadow [MaxSprites]Attributes
+	used   [MaxSprites / 32]uint32 // 確保済みエントリのビットマップ
+	dirty  bool
+
+	affineUsed uint32 // 確保済みアフィン行列のビットマップ
+}
+
+// NewManager スプライト管理を初期化（すべて非表示）
+// シャドウOAMは1KBあるため、IWRAMではなくヒープ（EWRAM）に確保する
+func NewManager() *Manager {
+	m := &Manager{dirty: true}
+	for i := range m.shadow {
+		m.shadow[i].Attr0 = attr0Disable
+	}
+	return m
+}
+
+// isUsed エントリが確保済みか
+func (m *Manager) isUsed(index int) bool {
+	return m.used[index/32]&(1<<(index%32)) != 0
+}
+
+// Alloc 空いているエントリを確保（番号の小さい方が前面に描かれる）
+// 確保したスプライトは8x8、タイル0、非表示の状態になる
+func (m *Manager) Alloc() (Handle, bool) {
+	for i := 0; i < MaxSprites; i++ {
+		if !m.isUsed(i) {
+			m.used[i/32] |= 1 << (i % 32)
+			m.reset(i)
+			return Handle(i), true
+		}
+	}
+	return 0, false
+}
+
+// AllocAt 指定したエントリを確保（前後関係を決めたい場合に使う）
+func (m *Manager) AllocAt(index int) (Handle, bool) {
+	if index < 0 || index >= MaxSprites || m.isUsed(index) {
+		return 0, false
+	}
+	m.used[index/32] |= 1 << (index % 32)
+	m.reset(index)
+	return Handle(index), true
+}
+
+// Free エントリを解放して非表示にする
+func (m *Manager) Free(h Handle) {
+	m.used[h/32] &^= 1 << (h % 32)
+	m.reset(int(h))
+}
+
+// reset エントリを初期状態（非表示）にする
+func (m *Manager) reset(index int) {
+	m.shadow[index].Reset()
+	m.dirty = true
+}
+
+// Sprite スプライトの属性を取得（変更すると次のCommitで転送される）
+func (m *Manager) Sprite(h Handle) *Attributes {
+	m.dirty = true
+	return &m.shadow[h]
+}
+
+// Count 確保済みのスプライトの数
+func (m *Manager) Count() int {
+	n := 0
+	for i := 0; i < MaxSprites; i++ {
+		if m.isUsed(i) {
+			n++
+		}
+	}
+	return n
+}
+
+// HideAll すべてのスプライトを非表示にする（確保したままにする）
+func (m *Manager) HideAll() {
+	for i := range m.shadow {
+		m.shadow[i].Hide()
+	}
+	m.dirty = true
+}
+
+// MarkDirty 次のCommitで必ず転送させる
+func (m *Manager) MarkDirty() {
+	m.dirty = true
+}
+
+// Commit シャドウOAMをOAMに転送（VBlank中に呼ぶ）
+func (m *Manager) Commit() {
+	if !m.dirty {
+		return
+	}
+	memory.DMA3Copy32(unsafe.Pointer(uintptr(RegOAM)), unsafe.Pointer(&m.shadow[0]), MaxSprites*2)
+	m.dirty = false
+}
@@ -0,0 +1,209 @@
+package sprite
+
+// OAM（スプライトの属性メモリ）の1エントリと、その属性を設定する関数
+
+// 属性0のビット
+const (
+	attr0Y        = 0x00FF
+	attr0Affine   = 1 << 8  // アフィン変換を使う
+	attr0Disable  = 1 << 9  // 非表示（通常スプライト）
+	attr0Double   = 1 << 9  // 描画範囲を2倍にする（アフィンスプライト）
+	attr0Blend    = 1 << 10 // 半透明
+	attr0ModeMask = 3 << 10
+	attr0Mosaic   = 1 << 12
+	attr0Color256 = 1 << 13 // 256色（8bpp）タイル
+	attr0Shape    = 3 << 14
+)
+
+// 属性1のビット
+const (
+	attr1X           = 0x01FF
+	attr1AffineIndex = 0x1F << 9 // アフィン行列の番号（アフィンスプライト）
+	attr1HFlip       = 1 << 12   // 左右反転（通常スプライト）
+	attr1VFlip       = 1 << 13   // 上下反転（通常スプライト）
+	attr1Size        = 3 << 14
+)
+
+// 属性2のビット
+const (
+	attr2Tile     = 0x03FF
+	attr2Priority = 3 << 10
+	attr2Palette  = 0xF << 12
+)
+
+// Size スプライトの形と大きさ（上位2bitが形、下位2bitが大きさ）
+type Size uint8
+
+const (
+	Size8x8   Size = 0<<2 | 0
+	Size16x16 Size = 0<<2 | 1
+	Size32x32 Size = 0<<2 | 2
+	Size64x64 Size = 0<<2 | 3
+	Size16x8  Size = 1<<2 | 0
+	Size32x8  Size = 1<<2 | 1
+	Size32x16 Size = 1<<2 | 2
+	Size64x32 Size = 1<<2 | 3
+	Size8x16  Size = 2<<2 | 0
+	Size8x32  Size = 2<<2 | 1
+	Size16x32 Size = 2<<2 | 2
+	Size32x64 Size = 2<<2 | 3
+)
+
+// sizeTable 形・大きさごとの幅と高さ
+var sizeTable = [3][4][2]uint8{
+	{{8, 8}, {16, 16}, {32, 32}, {64, 64}}, // 正方形
+	{{16, 8}, {32, 8}, {32, 16}, {64, 32}}, // 横長
+	{{8, 16}, {8, 32}, {16, 32}, {32, 64}}, // 縦長
+}
+
+// Dimensions 幅と高さ（ピクセル）
+func (s Size) Dimensions() (width, height int) {
+	shape := s >> 2
+	if shape > 2 {
+		return 0, 0
+	}
+	d := sizeTable[shape][s&3]
+	return int(d[0]), int(d[1])
+}
+
+// Tiles 使うタイルの数（4bppの8x8タイル単位、8bppでは2倍を使う）
+func (s Size) Tiles() int {
+	w, h := s.Dimensions()
+	return (w / 8) * (h / 8)
+}
+
+// Attributes OAMの1エントリ
+// 4つ目の値は4エントリごとに1つのアフィン行列の成分として使われる
+type Attributes struct {
+	Attr0  uint16
+	Attr1  uint16
+	Attr2  uint16
+	affine uint16
+}
+
+// SetPosition 左上の位置を設定（画面外は9bit/8bitで折り返して扱われる）
+func (a *Attributes) SetPosition(x, y int) {
+	a.Attr0 = a.Attr0&^attr0Y | uint16(y)&attr0Y
+	a.Attr1 = a.Attr1&^attr1X | uint16(x)&attr1X
+}
+
+// Position 左上の位置（画面の左・上にはみ出した分は負の値）
+func (a *Attributes) Position() (x, y int) {
+	x = int(a.Attr1 & attr1X)
+	if x >= 256 {
+		x -= 512
+	}
+	y = int(a.Attr0 & attr0Y)
+	if y >= 160 {
+		y -= 256
+	}
+	return x, y
+}
+
+// SetSize 形と大きさを設定
+func (a *Attributes) SetSize(size Size) {
+	a.Attr0 = a.Attr0&^attr0Shape | uint16(size>>2)<<14
+	a.Attr1 = a.Attr1&^attr1Size | uint16(size&3)<<14
+}
+
+// Size 形と大きさ
+func (a *Attributes) Size() Size {
+	return Size((a.Attr0>>14)<<2 | a.Attr1>>14)
+}
+
+// SetTile 先頭のタイル番号を設定（32バイト単位。Mode 3-5では512以降だけが使える）
+func (a *Attributes) SetTile(tile int) {
+	a.Attr2 = a.Attr2&^attr2Tile | uint16(tile)&attr2Tile
+}
+
+// Tile 先頭のタイル番号
+func (a *Attributes) Tile() int {
+	return int(a.Attr2 & attr2Tile)
+}
+
+// SetPalette 16色パレットのバンク（0-15）を設定（4bppタイルの場合）
+func (a *Attributes) SetPalette(bank int) {
+	a.Attr2 = a.Attr2&^attr2Palette | uint16(bank&0xF)<<12
+}
+
+// SetColor256 256色（8bpp）タイルを使うか設定
+func (a *Attributes) SetColor256(enabled bool) {
+	a.Attr0 = setBit(a.Attr0, attr0Color256, enabled)
+}
+
+// SetPriority 背景との前後関係（0が最前面、0-3）を設定
+// 同じ優先度のスプライト同士はOAMの番号が小さい方が前になる
+func (a *Attributes) SetPriority(priority int) {
+	a.Attr2 = a.Attr2&^attr2Priority | uint16(priority&3)<<10
+}
+
+// Priority 背景との前後関係
+func (a *Attributes) Priority() int {
+	return int(a.Attr2&attr2Priority) >> 10
+}
+
+// SetFlip 左右・上下反転を設定（アフィンスプライトでは無効）
+func (a *Attributes) SetFlip(horizontal, vertical bool) {
+	if a.IsAffine() {
+		return
+	}
+	a.Attr1 = setBit(a.Attr1, attr1HFlip, horizontal)
+	a.Attr1 = setBit(a.Attr1, attr1VFlip, vertical)
+}
+
+// SetBlend 半透明にするか設定（display.SetBlendの第1対象にOBJを含める）
+func (a *Attributes) SetBlend(enabled bool) {
+	mode := uint16(0)
+	if enabled {
+		mode = attr0Blend
+	}
+	a.Attr0 = a.Attr0&^attr0ModeMask | mode
+}
+
+// SetMosaic モザイクを有効にするか設定
+func (a *Attributes) SetMosaic(enabled bool) {
+	a.Attr0 = setBit(a.Attr0, attr0Mosaic, enabled)
+}
+
+// Reset 初期状態（8x8、タイル0、非表示）に戻す
+// 4つ目の値はアフィン行列の一部なので残す
+func (a *Attributes) Reset() {
+	a.Attr0 = attr0Disable
+	a.Attr1 = 0
+	a.Attr2 = 0
+}
+
+// Hide 非表示にする
+// アフィンスプライトは通常スプライトに戻る（表示するときはSetAffineし直す）
+func (a *Attributes) Hide() {
+	if a.IsAffine() {
+		a.Attr1 &^= attr1AffineIndex
+	}
+	a.Attr0 = a.Attr0&^(attr0Affine|attr0Double) | attr0Disable
+}
+
+// Show 表示する（通常スプライト）
+func (a *Attributes) Show() {
+	if a.IsAffine() {
+		return
+	}
+	a.Attr0 &^= attr0Disable
+}
+
+// IsHidden 非表示か
+func (a *Attributes) IsHidden() bool {
+	return a.Attr0&(attr0Affine|attr0Disable) == attr0Disable
+}
+
+// IsAffine アフィンスプライトか
+func (a *Attributes) IsAffine() bool {
+	return a.Attr0&attr0Affine != 0
+}
+
+// setBit bitを立てるか消す
+func setBit(v, bit uint16, on bool) uint16 {
+	if on {
+		return v | bit
+	}
+	return v &^ bit
+}
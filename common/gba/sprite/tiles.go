@@ -0,0 +1,35 @@
+package sprite
+
+import (
+	"unsafe"
+
+	"github.com/ryomak/gameboys/common/gba/memory"
+)
+
+// スプライト用タイル（OBJ VRAM）
+const (
+	RegOBJTiles = 0x06010000 // OBJタイルの先頭アドレス
+	TileBytes   = 32         // タイル番号1つ分のバイト数（4bppの8x8タイル）
+	MaxTiles    = 1024       // タイル番号の数（32KB）
+
+	// BitmapTileStart Mode 3-5で使える最初のタイル番号（それより前はビットマップと重なる）
+	BitmapTileStart = 512
+)
+
+// TileAddr タイル番号のVRAMアドレス
+func TileAddr(tile int) uintptr {
+	return RegOBJTiles + uintptr(tile)*TileBytes
+}
+
+// LoadTiles タイルデータをtile番目から書き込む（VRAMは16bit単位で書く）
+// 8bppのタイルは1枚で2つ分のタイル番号を使う
+func LoadTiles(tile int, data []uint16) {
+	if tile < 0 || tile >= MaxTiles || len(data) == 0 {
+		return
+	}
+	count := len(data)
+	if limit := (MaxTiles - tile) * TileBytes / 2; count > limit {
+		count = limit
+	}
+	memory.DMA3Copy16(unsafe.Pointer(TileAddr(tile)), unsafe.Pointer(&data[0]), uint32(count))
+}
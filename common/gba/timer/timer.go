@@ -0,0 +1,68 @@
+package timer
+
+import (
+	"runtime/volatile"
+	"unsafe"
+)
+
+// タイマー（4チャンネル、16bitのカウンタ）
+// カウンタがあふれるとリロード値から数え直し、割り込みを起こせる
+
+// レジスタアドレス（チャンネルごとに4バイトずつ並ぶ）
+const (
+	RegTM0CNT_L = 0x04000100 // カウンタ（書き込むとリロード値）
+	RegTM0CNT_H = 0x04000102 // 制御
+)
+
+// 制御フラグ
+const (
+	Prescale1    = 0      // 1サイクルごと（16.78MHz）
+	Prescale64   = 1      // 64サイクルごと
+	Prescale256  = 2      // 256サイクルごと
+	Prescale1024 = 3      // 1024サイクルごと
+	Cascade      = 1 << 2 // 前のチャンネルがあふれるごとに数える
+	IRQEnable    = 1 << 6 // あふれたときに割り込みを起こす
+	Enable       = 1 << 7
+)
+
+// CyclesPerFrame 1フレーム（228ライン）のCPUサイクル数
+const CyclesPerFrame = 280896
+
+// prescaleShift プリスケーラごとの1カウントのサイクル数（2の累乗の指数）
+var prescaleShift = [4]uint{0, 6, 8, 10}
+
+// registers チャンネルのカウンタと制御のレジスタ
+func registers(channel int) (*volatile.Register16, *volatile.Register16) {
+	base := uintptr(RegTM0CNT_L + (channel&3)*4)
+	return (*volatile.Register16)(unsafe.Pointer(base)), (*volatile.Register16)(unsafe.Pointer(base + 2))
+}
+
+// Start タイマーを開始
+// period: あふれるまでのカウント数（1-65536）、control: Prescale64|IRQEnable などの組み合わせ
+func Start(channel int, period int, control uint16) {
+	counter, cnt := registers(channel)
+	cnt.Set(0)
+	counter.Set(uint16(65536 - period))
+	cnt.Set(control | Enable)
+}
+
+// Stop タイマーを止める
+func Stop(channel int) {
+	_, cnt := registers(channel)
+	cnt.Set(0)
+}
+
+// Counter 現在のカウンタの値
+func Counter(channel int) uint16 {
+	counter, _ := registers(channel)
+	return counter.Get()
+}
+
+// PeriodFor 1フレームにtimes回あふれるカウント数（プリスケーラに合わせる）
+func PeriodFor(times int, prescale uint16) int {
+	if times < 1 {
+		times = 1
+	}
+	period := (CyclesPerFrame >> prescaleShift[prescale&3]) / times
+	return min(max(period, 1), 65536)
+}
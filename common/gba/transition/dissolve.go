@@ -0,0 +1,51 @@
+package transition
+
+import (
+	"github.com/ryomak/gameboys/common/gba/graphics"
+	"github.com/ryomak/gameboys/common/math"
+)
+
+// bayer4 4x4の組織的ディザの閾値（0-15）
+var bayer4 = [4][4]uint8{
+	{0, 8, 2, 10},
+	{12, 4, 14, 6},
+	{3, 11, 1, 9},
+	{15, 7, 13, 5},
+}
+
+// Dissolve ディザ模様で少しずつ塗りつぶして隠す（Mode 3/4）
+// ビットマップに描き込むため、Transition.Drawを毎フレームの描画の最後に呼ぶ
+// Mode 3で画面を毎フレーム描き直さない場合も、塗る点は増える一方なので重ねて描いてよい
+type Dissolve struct {
+	Mode4 bool
+	Color uint16 // 塗る色（Mode 3では15bitカラー、Mode 4ではパレットインデックス）
+}
+
+// Apply ハードウェアの設定はない
+func (d *Dissolve) Apply(amount math.Fixed) {}
+
+// Reset ハードウェアの設定はない
+func (d *Dissolve) Reset() {}
+
+// Draw 閾値が隠れ具合より小さい点を塗る
+func (d *Dissolve) Draw(amount math.Fixed) {
+	level := uint8(scaled(16, amount))
+	if level == 0 {
+		return
+	}
+	for y := 0; y < graphics.ScreenHeight; y++ {
+		row := &bayer4[y&3]
+		for phase := 0; phase < 4; phase++ {
+			if row[phase] >= level {
+				continue
+			}
+			for x := phase; x < graphics.ScreenWidth; x += 4 {
+				if d.Mode4 {
+					graphics.SetMode4Pixel(x, y, uint8(d.Color))
+				} else {
+					graphics.DrawPixel(x, y, d.Color)
+				}
+			}
+		}
+	}
+}
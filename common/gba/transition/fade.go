@@ -0,0 +1,34 @@
+package transition
+
+import (
+	"github.com/ryomak/gameboys/common/gba/display"
+	"github.com/ryomak/gameboys/common/math"
+)
+
+// Fade 黒（または白）を経由するフェード
+// カラー特殊効果の明るさ調整を使うため、すべてのモードで使える
+// 黒・白以外の色を経由する場合は、パレットを使うモードではpalette.Fadeを使う
+type Fade struct {
+	White  bool  // trueなら白、falseなら黒を経由する
+	Layers uint8 // 対象のレイヤー（display.BlendBG0などの組み合わせ、0ならすべて）
+}
+
+// Apply 明るさを設定
+func (f *Fade) Apply(amount math.Fixed) {
+	layers := f.Layers
+	if layers == 0 {
+		layers = display.BlendAll
+	}
+	mode := uint16(display.BlendDarken)
+	if f.White {
+		mode = display.BlendBrighten
+	}
+	display.SetBlend(mode, layers, 0)
+	display.SetBlendBrightness(scaled(display.BlendMax, amount))
+}
+
+// Reset 明るさを元に戻す
+func (f *Fade) Reset() {
+	display.DisableBlend()
+	display.SetBlendBrightness(0)
+}
@@ -0,0 +1,56 @@
+package transition
+
+import (
+	"github.com/ryomak/gameboys/common/gba/display"
+	"github.com/ryomak/gameboys/common/gba/graphics"
+	"github.com/ryomak/gameboys/common/math"
+)
+
+// Slide 画面全体をずらして画面外へ追い出す
+// ビットマップモード（Mode 3-5）ではBG2のアフィン変換の基準点を、
+// タイルモードではLayersの背景のスクロール位置を動かす
+// タイルの背景は端がつながって見えるため、Window 0 で画面に残る部分だけを表示する
+type Slide struct {
+	Direction Direction // 画面が動く向き
+	Layers    uint8     // タイルモードでずらす背景（display.WinBG0などの組み合わせ、0ならBG2のアフィン変換）
+}
+
+// Apply ずらす量を設定
+func (s *Slide) Apply(amount math.Fixed) {
+	dx, dy := 0, 0
+	switch s.Direction {
+	case Left:
+		dx = -scaled(graphics.ScreenWidth, amount)
+	case Right:
+		dx = scaled(graphics.ScreenWidth, amount)
+	case Up:
+		dy = -scaled(graphics.ScreenHeight, amount)
+	case Down:
+		dy = scaled(graphics.ScreenHeight, amount)
+	}
+	s.scroll(dx, dy)
+
+	// 画面に残る部分
+	display.SetWindow0(max(dx, 0), max(dy, 0),
+		min(graphics.ScreenWidth+dx, graphics.ScreenWidth), min(graphics.ScreenHeight+dy, graphics.ScreenHeight))
+	showWindow0()
+}
+
+// scroll 画面の内容を(dx, dy)だけ動かす
+func (s *Slide) scroll(dx, dy int) {
+	if s.Layers == 0 {
+		display.SetBG2Offset(-dx, -dy)
+		return
+	}
+	for bg := 0; bg < 4; bg++ {
+		if s.Layers&(1<<bg) != 0 {
+			display.SetBGScroll(bg, -dx, -dy)
+		}
+	}
+}
+
+// Reset 位置を戻してウィンドウを止める
+func (s *Slide) Reset() {
+	s.scroll(0, 0)
+	hideWindow0()
+}
@@ -0,0 +1,160 @@
+package transition
+
+import "github.com/ryomak/gameboys/common/math"
+
+// 画面切り替えの演出
+// 前の画面を隠し（Out）、隠れ切ったところで画面を切り替え、次の画面を現す（In）
+// ハードウェア（ウィンドウ・カラー特殊効果・スクロール）で行う効果はApplyでレジスタを設定し、
+// ビットマップに描き込む効果はOverlayを実装して、毎フレームの描画の最後にDrawで重ねる
+
+// Effect 画面の隠し方
+type Effect interface {
+	// Apply 隠れ具合を反映（0で全部見え、FixedOneで全部隠れる）。VBlank中に呼ぶ
+	Apply(amount math.Fixed)
+	// Reset 効果を止めてレジスタを元に戻す
+	Reset()
+}
+
+// Overlay ビットマップに描き込む効果
+type Overlay interface {
+	// Draw 隠れ具合に応じて描画中の画面に重ねる
+	Draw(amount math.Fixed)
+}
+
+// phase 進行状況
+type phase uint8
+
+const (
+	phaseIdle    phase = iota // 何もしていない
+	phaseOut                  // 隠している
+	phaseCovered              // 隠れたまま（StartOutの後）
+	phaseIn                   // 現している
+)
+
+// Transition 効果をフレーム数に沿って進める
+type Transition struct {
+	Effect Effect
+	Frames int // 隠す・現すそれぞれにかけるフレーム数
+
+	phase     phase
+	frame     int
+	roundTrip bool // 隠し終えたらそのまま現すか
+}
+
+// New 効果とフレーム数から作成
+func New(effect Effect, frames int) Transition {
+	return Transition{Effect: effect, Frames: frames}
+}
+
+// Start 隠してから現す（隠れ切ったフレームでUpdateがtrueを返す）
+func (t *Transition) Start() {
+	t.phase = phaseOut
+	t.frame = 0
+	t.roundTrip = true
+}
+
+// StartOut 隠すだけ（StartInを呼ぶまで隠れたまま）
+func (t *Transition) StartOut() {
+	t.phase = phaseOut
+	t.frame = 0
+	t.roundTrip = false
+}
+
+// StartIn 隠れた状態から現す
+func (t *Transition) StartIn() {
+	t.phase = phaseIn
+	t.frame = 0
+}
+
+// Update 1フレーム分進めて効果を反映（VBlank中に呼ぶ）
+// 画面が隠れ切ったフレームでtrueを返す。このとき次の画面に切り替える
+func (t *Transition) Update() bool {
+	switch t.phase {
+	case phaseOut:
+		t.frame++
+		if t.frame < t.Frames {
+			t.Effect.Apply(t.Amount())
+			return false
+		}
+		t.Effect.Apply(math.FixedOne)
+		t.frame = 0
+		if t.roundTrip {
+			t.phase = phaseIn
+		} else {
+			t.phase = phaseCovered
+		}
+		return true
+	case phaseCovered:
+		t.Effect.Apply(math.FixedOne)
+	case phaseIn:
+		t.frame++
+		if t.frame < t.Frames {
+			t.Effect.Apply(t.Amount())
+			return false
+		}
+		t.Stop()
+	}
+	return false
+}
+
+// Draw Overlayの効果を描画中の画面に重ねる（毎フレームの描画の最後に呼ぶ）
+func (t *Transition) Draw() {
+	if t.phase == phaseIdle {
+		return
+	}
+	if overlay, ok := t.Effect.(Overlay); ok {
+		overlay.Draw(t.Amount())
+	}
+}
+
+// Stop 途中でも終了して効果を元に戻す
+func (t *Transition) Stop() {
+	t.phase = phaseIdle
+	t.frame = 0
+	t.Effect.Reset()
+}
+
+// Active 演出中か（隠れたままの状態も含む）
+func (t *Transition) Active() bool {
+	return t.phase != phaseIdle
+}
+
+// Covered 画面が隠れ切っているか
+func (t *Transition) Covered() bool {
+	return t.phase == phaseCovered || (t.phase == phaseIn && t.frame == 0)
+}
+
+// Amount 現在の隠れ具合（0-FixedOne）
+func (t *Transition) Amount() math.Fixed {
+	if t.Frames <= 0 {
+		if t.phase == phaseIdle {
+			return 0
+		}
+		return math.FixedOne
+	}
+	progress := math.NewFixed(int32(t.frame)).Div(math.NewFixed(int32(t.Frames)))
+	switch t.phase {
+	case phaseOut:
+		return progress
+	case phaseCovered:
+		return math.FixedOne
+	case phaseIn:
+		return math.FixedOne.Sub(progress)
+	}
+	return 0
+}
+
+// Direction 効果の進む向き
+type Direction uint8
+
+const (
+	Left Direction = iota
+	Right
+	Up
+	Down
+)
+
+// scaled 長さlengthのamount倍（ピクセル単位）
+func scaled(length int, amount math.Fixed) int {
+	return int(math.NewFixed(int32(length)).Mul(amount).Round())
+}
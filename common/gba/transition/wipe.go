@@ -0,0 +1,103 @@
+package transition
+
+import (
+	"unsafe"
+
+	"github.com/ryomak/gameboys/common/gba/display"
+	"github.com/ryomak/gameboys/common/gba/graphics"
+	"github.com/ryomak/gameboys/common/gba/memory"
+	"github.com/ryomak/gameboys/common/math"
+)
+
+// ウィンドウを使う効果
+// Window 0 の内側だけを表示し、外側は背景色（パレット0番）になる
+// ゲーム側でWindow 0 を使っている場合は併用できない
+
+// showWindow0 Window 0 の内側だけを表示する
+func showWindow0() {
+	display.SetWindowIn(display.WinAll, 0)
+	display.SetWindowOut(0, 0)
+	display.EnableLayers(display.EnableWin0)
+}
+
+// hideWindow0 Window 0 を止める
+func hideWindow0() {
+	display.DisableLayers(display.EnableWin0)
+}
+
+// Wipe 画面の端から直線で隠す
+// Directionは隠す境界が進む向き（Rightなら左端から右へ隠れていく）
+type Wipe struct {
+	Direction Direction
+}
+
+// Apply 見えている範囲をウィンドウに設定
+func (w *Wipe) Apply(amount math.Fixed) {
+	x0, y0, x1, y1 := 0, 0, graphics.ScreenWidth, graphics.ScreenHeight
+	switch w.Direction {
+	case Left:
+		x1 -= scaled(graphics.ScreenWidth, amount)
+	case Right:
+		x0 += scaled(graphics.ScreenWidth, amount)
+	case Up:
+		y1 -= scaled(graphics.ScreenHeight, amount)
+	case Down:
+		y0 += scaled(graphics.ScreenHeight, amount)
+	}
+	display.SetWindow0(x0, y0, x1, y1)
+	showWindow0()
+}
+
+// Reset ウィンドウを止める
+func (w *Wipe) Reset() {
+	hideWindow0()
+}
+
+// irisLines アイリスの表の行数
+// HBlankごとに次の行の値を転送するため、最後の行のHBlankで1行先を読む
+const irisLines = graphics.ScreenHeight + 1
+
+// irisDMAChannel アイリスの転送に使うDMAチャンネル（HBlankの転送には優先度の高い0を使う）
+const irisDMAChannel = 0
+
+// Iris (X, Y)を中心に円を縮めて隠す
+// 行ごとのウィンドウの左右端を表にして、HBlank DMAでWIN0Hへ転送する
+// 表をDMAが読むため、Irisは作成後に移動しない場所（構造体のフィールドなど）に置く
+type Iris struct {
+	X, Y int // 円の中心（画面座標）
+
+	table [irisLines]uint16
+}
+
+// Apply 円の半径を求めて表を作り、DMAを開始（毎フレームVBlank中に呼ぶ）
+func (r *Iris) Apply(amount math.Fixed) {
+	radius := scaled(r.maxRadius(), math.FixedOne.Sub(amount))
+	for y := 0; y < irisLines; y++ {
+		dy := y - r.Y
+		if y >= graphics.ScreenHeight || dy <= -radius || dy >= radius {
+			r.table[y] = 0
+			continue
+		}
+		half := int(math.IntSqrt(int32(radius*radius - dy*dy)))
+		r.table[y] = display.WindowSpan(max(r.X-half, 0), min(r.X+half+1, graphics.ScreenWidth))
+	}
+
+	display.WIN0H.Set(r.table[0])
+	display.WIN0V.Set(display.WindowSpan(0, graphics.ScreenHeight))
+	memory.DMAStart(irisDMAChannel, unsafe.Pointer(uintptr(display.RegWIN0H)), unsafe.Pointer(&r.table[1]), 1,
+		memory.DMA16|memory.DMAStartHBlank|memory.DMARepeat|memory.DMASrcIncrement|memory.DMADstFixed)
+	showWindow0()
+}
+
+// maxRadius 中心から最も遠い画面の角までの距離（全体が見える半径）
+func (r *Iris) maxRadius() int {
+	dx := max(r.X, graphics.ScreenWidth-r.X)
+	dy := max(r.Y, graphics.ScreenHeight-r.Y)
+	return int(math.IntSqrt(int32(dx*dx+dy*dy))) + 1
+}
+
+// Reset DMAとウィンドウを止める
+func (r *Iris) Reset() {
+	memory.DMAStop(irisDMAChannel)
+	hideWindow0()
+}
@@ -0,0 +1,109 @@
+package ui
+
+import "github.com/ryomak/gameboys/common/gba/graphics"
+
+// ProgressBar 値の割合を示すバー
+// 塗りの色はバー全体に対する位置でランプから選ぶので、伸びるほど色が変わる
+type ProgressBar struct {
+	Box
+	Value, Max int32
+	Vertical   bool    // trueなら下から上へ、falseなら左から右へ伸びる
+	Ramp       []uint8 // 塗りの色（nilならTheme.Ramp）
+	Padding    int     // 枠と中身の間隔
+	Ticks      int     // 目盛りの分割数（0なら目盛りなし）
+}
+
+// Draw バーを描画
+func (b *ProgressBar) Draw() {
+	if !b.visible() {
+		return
+	}
+	x, y, w, h := b.bounds()
+	t := b.Theme
+
+	graphics.FillRectMode4(x, y, w, h, t.Empty)
+
+	// 中身の領域
+	ix := x + b.Padding
+	iy := y + b.Padding
+	iw := w - b.Padding*2
+	ih := h - b.Padding*2
+	length := iw
+	if b.Vertical {
+		length = ih
+	}
+	if length > 0 && iw > 0 && ih > 0 && b.Max > 0 {
+		value := b.Value
+		if value > b.Max {
+			value = b.Max
+		}
+		filled := int(int32(length) * value / b.Max)
+		for i := 0; i < filled; i++ {
+			color := t.rampColor(b.Ramp, i, length)
+			if b.Vertical {
+				graphics.FillRectMode4(ix, iy+ih-1-i, iw, 1, color)
+			} else {
+				graphics.FillRectMode4(ix+i, iy, 1, ih, color)
+			}
+		}
+	}
+
+	// 目盛り（両側から短い線）
+	if b.Ticks > 0 {
+		for i := 0; i <= b.Ticks; i++ {
+			if b.Vertical {
+				markY := y + (h-1)*i/b.Ticks
+				graphics.FillRectMode4(x, markY, 4, 1, t.Border)
+				graphics.FillRectMode4(x+w-4, markY, 4, 1, t.Border)
+			} else {
+				markX := x + (w-1)*i/b.Ticks
+				graphics.FillRectMode4(markX, y, 1, 4, t.Border)
+				graphics.FillRectMode4(markX, y+h-4, 1, 4, t.Border)
+			}
+		}
+	}
+
+	graphics.DrawRectMode4(x, y, w, h, t.Border)
+}
+
+// Meter 区切られたセグメントで値を示すメーター
+type Meter struct {
+	Box
+	Value    int32   // 点灯しているセグメント数
+	Segments int32   // セグメントの総数
+	Gap      int     // セグメントの間隔
+	Vertical bool    // trueなら下から上へ並べる
+	Ramp     []uint8 // 点灯色（nilならTheme.Ramp）
+}
+
+// Draw メーターを描画（消灯セグメントはTheme.Empty）
+func (m *Meter) Draw() {
+	if !m.visible() || m.Segments <= 0 {
+		return
+	}
+	x, y, w, h := m.bounds()
+	t := m.Theme
+	n := int(m.Segments)
+
+	length := w
+	if m.Vertical {
+		length = h
+	}
+	size := (length - m.Gap*(n-1)) / n
+	if size <= 0 {
+		return
+	}
+
+	for i := 0; i < n; i++ {
+		color := t.Empty
+		if int32(i) < m.Value {
+			color = t.rampColor(m.Ramp, i, n)
+		}
+		pos := i * (size + m.Gap)
+		if m.Vertical {
+			graphics.FillRectMode4(x, y+h-pos-size, w, size, color)
+		} else {
+			graphics.FillRectMode4(x+pos, y, size, h, color)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package ui
+
+import "github.com/ryomak/gameboys/common/gba/graphics"
+
+// IconShape アイコンの形
+type IconShape int
+
+const (
+	IconCircle IconShape = iota
+	IconSquare
+)
+
+// IconRow 同じアイコンを横に並べた表示（残機やスコアの丸など）
+// 各アイコンの大きさはRect.Heightで、Spacingごとに並べる
+type IconRow struct {
+	Box
+	Count   int32            // アイコンの数
+	Filled  int32            // 先頭から塗りつぶす数（Theme.Fill）
+	Marked  int32            // 先頭から枠を強調する数（Theme.Accent、それ以外はTheme.Empty）
+	Spacing int              // アイコンの間隔（0ならRect.Height+1）
+	Shape   IconShape        // 枠の形
+	Image   *graphics.Image8 // 指定すると塗りつぶしたアイコンの代わりに描画する画像
+}
+
+// Draw アイコンを描画
+func (r *IconRow) Draw() {
+	if !r.visible() {
+		return
+	}
+	x, y, _, h := r.bounds()
+	t := r.Theme
+	spacing := r.Spacing
+	if spacing <= 0 {
+		spacing = h + 1
+	}
+	radius := (h - 1) / 2
+
+	for i := int32(0); i < r.Count; i++ {
+		ix := x + int(i)*spacing
+
+		if i < r.Filled {
+			switch {
+			case r.Image != nil:
+				graphics.BlitMode4(ix, y, r.Image)
+				continue
+			case r.Shape == IconSquare:
+				graphics.FillRectMode4(ix, y, h, h, t.Fill)
+			default:
+				graphics.FillCircleMode4(ix+radius, y+radius, radius, t.Fill)
+			}
+		}
+
+		outline := t.Empty
+		if i < r.Marked {
+			outline = t.Accent
+		}
+		if r.Shape == IconSquare {
+			graphics.DrawRectMode4(ix, y, h, h, outline)
+		} else {
+			graphics.DrawCircleMode4(ix+radius, y+radius, radius, outline)
+		}
+	}
+}
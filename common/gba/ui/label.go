@@ -0,0 +1,71 @@
+package ui
+
+import "github.com/ryomak/gameboys/common/gba/graphics"
+
+// Align 文字の横方向の揃え
+type Align int
+
+const (
+	AlignLeft Align = iota
+	AlignCenter
+	AlignRight
+)
+
+// Label 文字列（と数値）を表示するラベル
+// 矩形の中で縦方向は中央に配置する
+type Label struct {
+	Box
+	Text      string
+	Value     int32 // ShowValueがtrueならTextの後に表示する数値
+	ShowValue bool
+	Align     Align
+	Scale     int  // 文字の拡大率（0なら1）
+	Fill      bool // trueならTheme.Backgroundで背景を塗る
+}
+
+// Draw ラベルを描画
+func (l *Label) Draw() {
+	if !l.visible() {
+		return
+	}
+	x, y, w, h := l.bounds()
+	t := l.Theme
+	scale := max(l.Scale, 1)
+
+	if l.Fill {
+		graphics.FillRectMode4(x, y, w, h, t.Background)
+	}
+
+	// 全体の幅
+	textW := graphics.TextWidth(l.Text)
+	width := textW
+	if l.ShowValue {
+		if textW > 0 {
+			width += graphics.FontAdvance * 2
+		}
+		width += graphics.NumberWidth(l.Value)
+	}
+	width *= scale
+	height := graphics.TextHeight(l.Text)
+	if l.ShowValue {
+		height = max(height, graphics.FontHeight)
+	}
+	height *= scale
+
+	tx := x
+	switch l.Align {
+	case AlignCenter:
+		tx = x + (w-width)/2
+	case AlignRight:
+		tx = x + w - width
+	}
+	ty := y + (h-height)/2
+
+	graphics.DrawTextScaledMode4(tx, ty, l.Text, scale, t.Text)
+	if l.ShowValue {
+		if textW > 0 {
+			tx += (textW + graphics.FontAdvance*2) * scale
+		}
+		graphics.DrawNumberScaledMode4(tx, ty, l.Value, scale, t.Text)
+	}
+}
@@ -0,0 +1,86 @@
+package ui
+
+import "github.com/ryomak/gameboys/common/gba/graphics"
+
+// NineSlice 9分割した画像による枠
+// 四隅はそのまま、辺と中央は並べて敷き詰めるので、任意の大きさのパネルを描ける
+type NineSlice struct {
+	Image                    *graphics.Image8
+	Left, Top, Right, Bottom int // 四辺の枠の太さ（ピクセル）
+}
+
+// Draw 矩形いっぱいに描画
+func (s *NineSlice) Draw(x, y, width, height int) {
+	img := s.Image
+	if img == nil || width <= 0 || height <= 0 {
+		return
+	}
+
+	// 画像側の中央部分のサイズ
+	midW := img.Width - s.Left - s.Right
+	midH := img.Height - s.Top - s.Bottom
+	// 描画先の中央部分のサイズ
+	dstW := width - s.Left - s.Right
+	dstH := height - s.Top - s.Bottom
+
+	right := x + width - s.Right
+	bottom := y + height - s.Bottom
+	srcRight := img.Width - s.Right
+	srcBottom := img.Height - s.Bottom
+
+	// 上段
+	graphics.BlitRegionMode4(x, y, img, 0, 0, s.Left, s.Top)
+	s.tile(x+s.Left, y, dstW, s.Top, s.Left, 0, midW, s.Top)
+	graphics.BlitRegionMode4(right, y, img, srcRight, 0, s.Right, s.Top)
+	// 中段
+	s.tile(x, y+s.Top, s.Left, dstH, 0, s.Top, s.Left, midH)
+	s.tile(x+s.Left, y+s.Top, dstW, dstH, s.Left, s.Top, midW, midH)
+	s.tile(right, y+s.Top, s.Right, dstH, srcRight, s.Top, s.Right, midH)
+	// 下段
+	graphics.BlitRegionMode4(x, bottom, img, 0, srcBottom, s.Left, s.Bottom)
+	s.tile(x+s.Left, bottom, dstW, s.Bottom, s.Left, srcBottom, midW, s.Bottom)
+	graphics.BlitRegionMode4(right, bottom, img, srcRight, srcBottom, s.Right, s.Bottom)
+}
+
+// tile 画像の一部(sx, sy, sw, sh)を描画先(x, y, w, h)に敷き詰める
+func (s *NineSlice) tile(x, y, w, h, sx, sy, sw, sh int) {
+	if w <= 0 || h <= 0 || sw <= 0 || sh <= 0 {
+		return
+	}
+	for ty := 0; ty < h; ty += sh {
+		for tx := 0; tx < w; tx += sw {
+			graphics.BlitRegionMode4(x+tx, y+ty, s.Image, sx, sy, min(sw, w-tx), min(sh, h-ty))
+		}
+	}
+}
+
+// Panel 枠付きのパネル
+type Panel struct {
+	Box
+	Slice  *NineSlice // 指定すると画像で描画（nilならテーマの色で描画）
+	Inset  int        // 内側の枠（Theme.Accent）の外枠からの距離（0なら内側の枠なし）
+	Shadow int        // 影（Theme.Shadow）のずらし量（0なら影なし）
+}
+
+// Draw パネルを描画
+func (p *Panel) Draw() {
+	if !p.visible() {
+		return
+	}
+	x, y, w, h := p.bounds()
+	t := p.Theme
+
+	if p.Shadow > 0 {
+		graphics.FillRectMode4(x+p.Shadow, y+p.Shadow, w, h, t.Shadow)
+	}
+	if p.Slice != nil {
+		p.Slice.Draw(x, y, w, h)
+		return
+	}
+
+	graphics.FillRectMode4(x, y, w, h, t.Background)
+	graphics.DrawRectMode4(x, y, w, h, t.Border)
+	if p.Inset > 0 && w > p.Inset*2 && h > p.Inset*2 {
+		graphics.DrawRectMode4(x+p.Inset, y+p.Inset, w-p.Inset*2, h-p.Inset*2, t.Accent)
+	}
+}
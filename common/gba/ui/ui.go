@@ -0,0 +1,88 @@
+package ui
+
+import "github.com/ryomak/gameboys/common/util"
+
+// Mode 4用のUIウィジェット
+// 各ウィジェットは配置する矩形（Rect）と色のテーマ（Theme）を持ち、Drawでバックバッファに描画する
+
+// MaxWidgets HUDに登録できるウィジェットの最大数
+const MaxWidgets = 32
+
+// Theme ウィジェットの配色（パレットインデックス）
+type Theme struct {
+	Background uint8   // パネル・ラベルの背景
+	Border     uint8   // 外枠
+	Accent     uint8   // 内側の枠、強調表示
+	Shadow     uint8   // 影
+	Text       uint8   // 文字
+	Fill       uint8   // アイコンの塗り
+	Empty      uint8   // バー・メーター・アイコンの空き部分
+	Ramp       []uint8 // バー・メーターの塗り（先頭が始点側、末尾が終点側の色）
+}
+
+// rampColor 長さlengthのうち位置posの色をランプから取得
+func (t *Theme) rampColor(ramp []uint8, pos, length int) uint8 {
+	if len(ramp) == 0 {
+		ramp = t.Ramp
+	}
+	if len(ramp) == 0 {
+		return t.Fill
+	}
+	if length <= 0 {
+		return ramp[0]
+	}
+	i := pos * len(ramp) / length
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(ramp) {
+		i = len(ramp) - 1
+	}
+	return ramp[i]
+}
+
+// Box ウィジェット共通の配置情報
+type Box struct {
+	Rect   util.Rect // 配置する矩形（画面座標）
+	Theme  *Theme    // 配色
+	Hidden bool      // trueなら描画しない
+}
+
+// visible 描画できる状態か
+func (b *Box) visible() bool {
+	return !b.Hidden && b.Theme != nil && !b.Rect.IsEmpty()
+}
+
+// bounds 矩形をintで取得
+func (b *Box) bounds() (x, y, w, h int) {
+	return int(b.Rect.X), int(b.Rect.Y), int(b.Rect.Width), int(b.Rect.Height)
+}
+
+// Widget 描画できるUI部品
+type Widget interface {
+	Draw()
+}
+
+// HUD ウィジェットをまとめて描画する
+// 登録順に描画されるため、手前に表示するものを後から登録する
+type HUD struct {
+	widgets [MaxWidgets]Widget
+	count   int
+}
+
+// Add ウィジェットを登録（空きがなければfalse）
+func (h *HUD) Add(w Widget) bool {
+	if h.count >= MaxWidgets {
+		return false
+	}
+	h.widgets[h.count] = w
+	h.count++
+	return true
+}
+
+// Draw 登録されたウィジェットをすべて描画
+func (h *HUD) Draw() {
+	for i := 0; i < h.count; i++ {
+		h.widgets[i].Draw()
+	}
+}
@@ -0,0 +1,99 @@
+package math
+
+// Mat3 3x3行列（回転・拡大縮小用、固定小数点）
+// M[行][列]。ベクトルは列ベクトルとして右から掛ける
+type Mat3 struct {
+	M [3][3]Fixed
+}
+
+// Identity3 単位行列
+func Identity3() Mat3 {
+	return Mat3{M: [3][3]Fixed{
+		{FixedOne, 0, 0},
+		{0, FixedOne, 0},
+		{0, 0, FixedOne},
+	}}
+}
+
+// RotationX X軸まわりの回転行列（angle: 0-255 が 0-360度）
+func RotationX(angle int32) Mat3 {
+	c, s := Cos(angle), Sin(angle)
+	return Mat3{M: [3][3]Fixed{
+		{FixedOne, 0, 0},
+		{0, c, -s},
+		{0, s, c},
+	}}
+}
+
+// RotationY Y軸まわりの回転行列
+func RotationY(angle int32) Mat3 {
+	c, s := Cos(angle), Sin(angle)
+	return Mat3{M: [3][3]Fixed{
+		{c, 0, s},
+		{0, FixedOne, 0},
+		{-s, 0, c},
+	}}
+}
+
+// RotationZ Z軸まわりの回転行列
+func RotationZ(angle int32) Mat3 {
+	c, s := Cos(angle), Sin(angle)
+	return Mat3{M: [3][3]Fixed{
+		{c, -s, 0},
+		{s, c, 0},
+		{0, 0, FixedOne},
+	}}
+}
+
+// RotationYXZ Y（ヨー）、X（ピッチ）、Z（ロール）の順に回した回転行列
+// ベクトルにはZ、X、Yの順に適用される
+func RotationYXZ(yaw, pitch, roll int32) Mat3 {
+	return RotationY(yaw).Mul(RotationX(pitch)).Mul(RotationZ(roll))
+}
+
+// Scale3 拡大縮小行列
+func Scale3(x, y, z Fixed) Mat3 {
+	return Mat3{M: [3][3]Fixed{
+		{x, 0, 0},
+		{0, y, 0},
+		{0, 0, z},
+	}}
+}
+
+// Mul 行列の積（m × other）
+func (m Mat3) Mul(other Mat3) Mat3 {
+	var r Mat3
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			r.M[i][j] = m.M[i][0].Mul(other.M[0][j]).
+				Add(m.M[i][1].Mul(other.M[1][j])).
+				Add(m.M[i][2].Mul(other.M[2][j]))
+		}
+	}
+	return r
+}
+
+// MulVec3 ベクトルを変換（m × v）
+func (m Mat3) MulVec3(v Vec3) Vec3 {
+	return Vec3{
+		X: m.M[0][0].Mul(v.X).Add(m.M[0][1].Mul(v.Y)).Add(m.M[0][2].Mul(v.Z)),
+		Y: m.M[1][0].Mul(v.X).Add(m.M[1][1].Mul(v.Y)).Add(m.M[1][2].Mul(v.Z)),
+		Z: m.M[2][0].Mul(v.X).Add(m.M[2][1].Mul(v.Y)).Add(m.M[2][2].Mul(v.Z)),
+	}
+}
+
+// Transpose 転置行列（回転行列なら逆行列）
+func (m Mat3) Transpose() Mat3 {
+	var r Mat3
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			r.M[i][j] = m.M[j][i]
+		}
+	}
+	return r
+}
+
+// Row i行目をベクトルとして取得
+func (m Mat3) Row(i int) Vec3 {
+	return Vec3{X: m.M[i][0], Y: m.M[i][1], Z: m.M[i][2]}
+}
@@ -0,0 +1,63 @@
+package math
+
+import "testing"
+
+// nearVec3 2つのベクトルの各成分の差がtol以内か
+func nearVec3(a, b Vec3, tol Fixed) bool {
+	return a.X.Sub(b.X).Abs() <= tol && a.Y.Sub(b.Y).Abs() <= tol && a.Z.Sub(b.Z).Abs() <= tol
+}
+
+func TestMat3Identity(t *testing.T) {
+	v := NewVec3(3, -4, 5)
+	result := Identity3().MulVec3(v)
+	if result != v {
+		t.Errorf("Identity failed: got %v, want %v", result, v)
+	}
+}
+
+func TestMat3Rotation(t *testing.T) {
+	tol := FixedOne >> 8
+
+	tests := []struct {
+		name string
+		m    Mat3
+		in   Vec3
+		want Vec3
+	}{
+		{"Y軸90度", RotationY(AngleQuarter), NewVec3(1, 0, 0), NewVec3(0, 0, -1)},
+		{"X軸90度", RotationX(AngleQuarter), NewVec3(0, 1, 0), NewVec3(0, 0, 1)},
+		{"Z軸90度", RotationZ(AngleQuarter), NewVec3(1, 0, 0), NewVec3(0, 1, 0)},
+		{"Z軸180度", RotationZ(AngleHalf), NewVec3(2, 3, 0), NewVec3(-2, -3, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.m.MulVec3(tt.in)
+			if !nearVec3(result, tt.want, tol) {
+				t.Errorf("got %v, want %v", result, tt.want)
+			}
+		})
+	}
+}
+
+func TestMat3MulTranspose(t *testing.T) {
+	m := RotationYXZ(40, 20, 70)
+	result := m.Mul(m.Transpose())
+
+	// 回転行列と転置の積は単位行列
+	identity := Identity3()
+	for i := 0; i < 3; i++ {
+		if !nearVec3(result.Row(i), identity.Row(i), FixedOne>>7) {
+			t.Errorf("row %d: got %v, want %v", i, result.Row(i), identity.Row(i))
+		}
+	}
+}
+
+func TestMat3Scale(t *testing.T) {
+	m := Scale3(NewFixed(2), FixedHalf, NewFixed(3))
+	result := m.MulVec3(NewVec3(4, 4, 4))
+	expected := NewVec3(8, 2, 12)
+	if result != expected {
+		t.Errorf("Scale failed: got %v, want %v", result, expected)
+	}
+}
@@ -70,6 +70,95 @@ func (c *Camera) Project(worldPos Vec3, screenWidth, screenHeight int32) Project
 	}
 }
 
+// View カメラの座標系（ビュー空間への変換）
+// X軸が右、Y軸が上、Z軸が前方（奥行き）
+type View struct {
+	Position Vec3 // カメラの位置
+	Right    Vec3 // 右方向（単位ベクトル）
+	Up       Vec3 // 上方向（単位ベクトル）
+	Forward  Vec3 // 前方向（単位ベクトル）
+	Near     Fixed
+	Far      Fixed
+}
+
+// Basis カメラの右・上・前方向の単位ベクトルを求める
+// 真上や真下を向いている場合は、ワールドのZ軸を上方向の基準にする
+func (c *Camera) Basis() (right, up, forward Vec3) {
+	forward = c.Target.Sub(c.Position).Normalize()
+	if forward == (Vec3{}) {
+		forward = NewVec3(0, 0, 1)
+	}
+
+	worldUp := NewVec3(0, 1, 0)
+	right = worldUp.Cross(forward)
+	// 前方向が上方向とほぼ平行なら外積が0に近くなる
+	if right.X.Abs()+right.Y.Abs()+right.Z.Abs() < FixedOne>>8 {
+		worldUp = NewVec3(0, 0, 1)
+		right = worldUp.Cross(forward)
+	}
+	right = right.Normalize()
+	up = forward.Cross(right)
+	return right, up, forward
+}
+
+// View ビュー空間への変換を作成（フレームごとに1回求めて使い回す）
+func (c *Camera) View() View {
+	right, up, forward := c.Basis()
+	return View{
+		Position: c.Position,
+		Right:    right,
+		Up:       up,
+		Forward:  forward,
+		Near:     c.Near,
+		Far:      c.Far,
+	}
+}
+
+// ToView ワールド座標をビュー空間の座標に変換
+func (v *View) ToView(worldPos Vec3) Vec3 {
+	rel := worldPos.Sub(v.Position)
+	return Vec3{
+		X: rel.Dot(v.Right),
+		Y: rel.Dot(v.Up),
+		Z: rel.Dot(v.Forward),
+	}
+}
+
+// DirectionToView ワールド空間の方向ベクトルをビュー空間に変換（平行移動なし）
+func (v *View) DirectionToView(dir Vec3) Vec3 {
+	return Vec3{
+		X: dir.Dot(v.Right),
+		Y: dir.Dot(v.Up),
+		Z: dir.Dot(v.Forward),
+	}
+}
+
+// ProjectView ビュー空間の座標を画面座標に変換
+// スケールは Near / 奥行き（Nearは画面までの距離として扱う）
+// Nearより手前は非表示。画面座標は画面外でも設定される
+func (v *View) ProjectView(viewPos Vec3, screenWidth, screenHeight int32) ProjectionResult {
+	if viewPos.Z < v.Near || viewPos.Z <= 0 {
+		return ProjectionResult{Visible: false}
+	}
+
+	scale := v.Near.Div(viewPos.Z)
+	screenX := screenWidth/2 + viewPos.X.Mul(scale).Round()
+	screenY := screenHeight/2 - viewPos.Y.Mul(scale).Round()
+
+	// 画面外判定（マージン付き）
+	margin := int32(32)
+	visible := viewPos.Z <= v.Far &&
+		screenX >= -margin && screenX < screenWidth+margin &&
+		screenY >= -margin && screenY < screenHeight+margin
+
+	return ProjectionResult{
+		ScreenX: screenX,
+		ScreenY: screenY,
+		Scale:   scale,
+		Visible: visible,
+	}
+}
+
 // ProjectSimple 簡易射影変換（カメラなし）
 // Z座標から直接スケールを計算
 func ProjectSimple(pos Vec3, screenWidth, screenHeight int32, baseDepth Fixed) ProjectionResult {
@@ -135,3 +135,71 @@ func TestCamera_Project(t *testing.T) {
 		t.Errorf("ScreenY should be near center: got %d, want near %d", result.ScreenY, centerY)
 	}
 }
+
+func TestCamera_Basis(t *testing.T) {
+	tol := FixedOne >> 8
+
+	camera := NewCamera()
+	camera.Position = NewVec3(0, 0, -300)
+	camera.Target = NewVec3(0, 0, 0)
+
+	right, up, forward := camera.Basis()
+	if !nearVec3(right, NewVec3(1, 0, 0), tol) ||
+		!nearVec3(up, NewVec3(0, 1, 0), tol) ||
+		!nearVec3(forward, NewVec3(0, 0, 1), tol) {
+		t.Errorf("Basis failed: right=%v up=%v forward=%v", right, up, forward)
+	}
+
+	// 真上を向いていても基底が求まる
+	camera.Target = NewVec3(0, 500, -300)
+	right, up, forward = camera.Basis()
+	if !nearVec3(forward, NewVec3(0, 1, 0), tol) {
+		t.Errorf("forward should point up: got %v", forward)
+	}
+	if right.Dot(forward).Abs() > tol || up.Dot(forward).Abs() > tol || right.Dot(up).Abs() > tol {
+		t.Errorf("Basis should be orthogonal: right=%v up=%v forward=%v", right, up, forward)
+	}
+	if right.Length().Sub(FixedOne).Abs() > tol {
+		t.Errorf("right should be unit length: got %v", right.Length().ToFloat())
+	}
+}
+
+func TestView_ProjectView(t *testing.T) {
+	// ProjectSimple(baseDepth=300)と同じ見え方のカメラ
+	camera := NewCamera()
+	camera.Position = NewVec3(0, 0, -300)
+	camera.Target = NewVec3(0, 0, 0)
+	camera.Near = NewFixed(300)
+	camera.Far = NewFixed(2000)
+	view := camera.View()
+
+	positions := []Vec3{
+		NewVec3(0, 0, 0),
+		NewVec3(50, 100, 422),
+		NewVec3(-30, 60, 100),
+	}
+
+	for _, pos := range positions {
+		want := ProjectSimple(pos, 240, 160, NewFixed(300))
+		got := view.ProjectView(view.ToView(pos), 240, 160)
+		if !got.Visible {
+			t.Errorf("%v should be visible", pos)
+		}
+		if abs32(got.ScreenX-want.ScreenX) > 1 || abs32(got.ScreenY-want.ScreenY) > 1 {
+			t.Errorf("%v: got (%d, %d), want (%d, %d)", pos, got.ScreenX, got.ScreenY, want.ScreenX, want.ScreenY)
+		}
+	}
+
+	// ニアプレーンより手前は非表示
+	if view.ProjectView(view.ToView(NewVec3(0, 0, -100)), 240, 160).Visible {
+		t.Error("Point in front of near plane should not be visible")
+	}
+}
+
+// abs32 int32の絶対値
+func abs32(x int32) int32 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -45,7 +45,7 @@ func RandFixedRange(min, max Fixed) Fixed {
 		return min
 	}
 	range_ := max - min
-	return min + RandFixed().Mul(range_).Div(NewFixed(65536))
+	return min + RandFixed().Mul(range_)
 }
 
 // RandBool ランダムなbool値を生成
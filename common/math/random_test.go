@@ -0,0 +1,30 @@
+package math
+
+import "testing"
+
+func TestRandFixedRange(t *testing.T) {
+	SetSeed(12345)
+	min := NewFixed(-3)
+	max := NewFixed(5)
+
+	var lo, hi bool
+	for i := 0; i < 1000; i++ {
+		v := RandFixedRange(min, max)
+		if v < min || v >= max {
+			t.Fatalf("RandFixedRange = %v, want [%v, %v)", v.ToFloat(), min.ToFloat(), max.ToFloat())
+		}
+		if v < NewFixed(-2) {
+			lo = true
+		}
+		if v > NewFixed(4) {
+			hi = true
+		}
+	}
+	if !lo || !hi {
+		t.Error("RandFixedRange should cover the whole range")
+	}
+
+	if got := RandFixedRange(max, min); got != max {
+		t.Errorf("RandFixedRange(max, min) = %v, want %v", got.ToFloat(), max.ToFloat())
+	}
+}
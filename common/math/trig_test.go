@@ -0,0 +1,28 @@
+package math
+
+import "testing"
+
+func TestSinCos(t *testing.T) {
+	tol := FixedOne >> 10
+
+	tests := []struct {
+		angle int32
+		sin   Fixed
+		cos   Fixed
+	}{
+		{0, 0, FixedOne},
+		{AngleQuarter / 2, NewFixedFloat(0.70710678), NewFixedFloat(0.70710678)},
+		{AngleQuarter, FixedOne, 0},
+		{AngleHalf, 0, -FixedOne},
+		{AngleHalf + AngleQuarter, -FixedOne, 0},
+	}
+
+	for _, tt := range tests {
+		if d := Sin(tt.angle).Sub(tt.sin).Abs(); d > tol {
+			t.Errorf("Sin(%d) = %v, want %v", tt.angle, Sin(tt.angle).ToFloat(), tt.sin.ToFloat())
+		}
+		if d := Cos(tt.angle).Sub(tt.cos).Abs(); d > tol {
+			t.Errorf("Cos(%d) = %v, want %v", tt.angle, Cos(tt.angle).ToFloat(), tt.cos.ToFloat())
+		}
+	}
+}
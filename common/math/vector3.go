@@ -92,7 +92,14 @@ func (v Vec3) Length() Fixed {
 }
 
 // Normalize 正規化（長さ1にする）
+// 成分が大きいと長さの二乗が16.16形式に収まらないため、
+// 先に最大成分で割って各成分を1以下にしてから長さを求める
 func (v Vec3) Normalize() Vec3 {
+	m := v.X.Abs().Max(v.Y.Abs()).Max(v.Z.Abs())
+	if m == 0 {
+		return v
+	}
+	v = v.Div(m)
 	length := v.Length()
 	if length == 0 {
 		return v
@@ -83,3 +83,15 @@ func TestDistance3(t *testing.T) {
 		t.Errorf("Distance3 failed: got %v, want %v", result.ToFloat(), expected.ToFloat())
 	}
 }
+
+func TestVec3NormalizeLarge(t *testing.T) {
+	// 成分が大きいと長さの二乗が16.16形式に収まらない
+	v := NewVec3(0, 300, 400)
+	result := v.Normalize()
+
+	expected := NewVec3Float(0, 0.6, 0.8)
+	tol := FixedOne >> 8
+	if result.X.Sub(expected.X).Abs() > tol || result.Y.Sub(expected.Y).Abs() > tol || result.Z.Sub(expected.Z).Abs() > tol {
+		t.Errorf("Normalize failed: got %v, want %v", result, expected)
+	}
+}
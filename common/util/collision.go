@@ -50,6 +50,46 @@ func (r Rect) Bottom() int32 {
 	return r.Y + r.Height
 }
 
+// IsEmpty 面積が0か
+func (r Rect) IsEmpty() bool {
+	return r.Width <= 0 || r.Height <= 0
+}
+
+// Union 2つの矩形を含む最小の矩形
+func (r Rect) Union(other Rect) Rect {
+	if r.IsEmpty() {
+		return other
+	}
+	if other.IsEmpty() {
+		return r
+	}
+	x0 := Min(r.X, other.X)
+	y0 := Min(r.Y, other.Y)
+	x1 := Max(r.Right(), other.Right())
+	y1 := Max(r.Bottom(), other.Bottom())
+	return Rect{X: x0, Y: y0, Width: x1 - x0, Height: y1 - y0}
+}
+
+// Intersection 2つの矩形の重なり部分（重ならなければ空の矩形）
+func (r Rect) Intersection(other Rect) Rect {
+	x0 := Max(r.X, other.X)
+	y0 := Max(r.Y, other.Y)
+	x1 := Min(r.Right(), other.Right())
+	y1 := Min(r.Bottom(), other.Bottom())
+	if x1 <= x0 || y1 <= y0 {
+		return Rect{}
+	}
+	return Rect{X: x0, Y: y0, Width: x1 - x0, Height: y1 - y0}
+}
+
+// Area 面積
+func (r Rect) Area() int32 {
+	if r.IsEmpty() {
+		return 0
+	}
+	return r.Width * r.Height
+}
+
 // Circle 円（固定小数点座標）
 type Circle struct {
 	X, Y   math.Fixed
@@ -5,6 +5,7 @@ import (
 	"github.com/ryomak/gameboys/common/gba/graphics"
 	"github.com/ryomak/gameboys/common/gba/input"
 	"github.com/ryomak/gameboys/common/math"
+	"github.com/ryomak/gameboys/common/util"
 )
 
 const (
@@ -15,7 +16,6 @@ const (
 // Ball プレイヤーが操作するボール
 type Ball struct {
 	x, y     int32
-	prevX, prevY int32  // 前フレームの位置
 	vx, vy   int32
 	color    uint16
 }
@@ -25,8 +25,6 @@ func NewBall(x, y int32, color uint16) *Ball {
 	return &Ball{
 		x:     x,
 		y:     y,
-		prevX: x,
-		prevY: y,
 		vx:    0,
 		vy:    0,
 		color: color,
@@ -35,10 +33,6 @@ func NewBall(x, y int32, color uint16) *Ball {
 
 // Update ボールの状態を更新
 func (b *Ball) Update(keys *input.KeyState) {
-	// 前フレームの位置を保存
-	b.prevX = b.x
-	b.prevY = b.y
-
 	// キー入力で速度を変更
 	b.vx = 0
 	b.vy = 0
@@ -75,26 +69,9 @@ func (b *Ball) Update(keys *input.KeyState) {
 	}
 }
 
-// Erase 前フレームのボールを消す
-func (b *Ball) Erase() {
-	// 移動していない場合は消さない
-	if b.x == b.prevX && b.y == b.prevY {
-		return
-	}
-
-	// 前の位置を黒で塗りつぶす
-	graphics.FillCircle(int(b.prevX), int(b.prevY), ballRadius, graphics.ColorBlack)
-
-	// その位置にあった星を再描画
-	for i := 0; i < len(stars); i++ {
-		dx := stars[i].x - b.prevX
-		dy := stars[i].y - b.prevY
-		dist := dx*dx + dy*dy
-		// 星が消された範囲にある場合は再描画
-		if dist <= int32(ballRadius*ballRadius) {
-			graphics.DrawPixel(int(stars[i].x), int(stars[i].y), stars[i].color)
-		}
-	}
+// Bounds ボールの描画範囲（差分描画用）
+func (b *Ball) Bounds() util.Rect {
+	return util.NewRect(b.x-ballRadius, b.y-ballRadius, ballRadius*2+1, ballRadius*2+1)
 }
 
 // Draw ボールを描画
@@ -135,15 +112,15 @@ func InitUI() {
 	graphics.FillRect(0, 0, graphics.ScreenWidth, 10, graphics.ColorDarkGray)
 }
 
-// UpdateUI UIを更新（フレームカウンターのみ）
+// UpdateUI UIを更新（フレームカウンターのみ、背景レイヤーに描画される）
 var lastDisplayFrame int32 = -1
 
-func UpdateUI(frame uint32) {
+func UpdateUI(frame uint32) bool {
 	displayFrame := int32((frame / 60) % 10)
 
 	// 前回と同じ場合は更新しない
 	if displayFrame == lastDisplayFrame {
-		return
+		return false
 	}
 
 	// フレームカウンター領域をクリア
@@ -155,6 +132,7 @@ func UpdateUI(frame uint32) {
 	}
 
 	lastDisplayFrame = displayFrame
+	return true
 }
 
 func main() {
@@ -174,11 +152,15 @@ func main() {
 	// フレームカウンター
 	var frame uint32 = 0
 
-	// 初回描画：画面全体を初期化
+	// 差分描画の初期化：背景（星とUI）を背景レイヤーに描画し、ボールを登録
+	renderer := graphics.NewDirtyRenderer()
+	renderer.BeginBackground()
 	graphics.ClearScreen(graphics.ColorBlack)
 	DrawStars()
 	InitUI()
-	ball.Draw()
+	renderer.EndBackground()
+	renderer.InvalidateAll()
+	renderer.Add(ball)
 
 	// メインループ
 	for {
@@ -196,15 +178,12 @@ func main() {
 		// 更新処理
 		ball.Update(keys)
 
-		// 描画処理（差分描画のみ）
-		// 前の位置のボールを消す
-		ball.Erase()
-
-		// 新しい位置にボールを描画
-		ball.Draw()
-
 		// UI更新（フレームカウンターのみ）
-		UpdateUI(frame)
+		renderer.BeginBackground()
+		if UpdateUI(frame) {
+			renderer.Invalidate(util.NewRect(220, 3, 20, 4))
+		}
+		renderer.EndBackground()
 
 		// Aボタンで色変更
 		if keys.IsPressed(input.KeyA) {
@@ -218,17 +197,24 @@ func main() {
 			}
 			colorIndex := (frame / 10) % uint32(len(colors))
 			ball.color = colors[colorIndex]
+			renderer.Invalidate(ball.Bounds()) // 止まっていても新しい色で描き直す
 		}
 
 		// Bボタンで星の色変更
 		if keys.IsPressed(input.KeyB) {
+			renderer.BeginBackground()
 			for i := 0; i < len(stars); i++ {
 				stars[i].color = uint16(math.RandInt(32768))
 				// 星を再描画
 				graphics.DrawPixel(int(stars[i].x), int(stars[i].y), stars[i].color)
 			}
+			renderer.EndBackground()
+			renderer.InvalidateAll()
 		}
 
+		// 描画処理（変化した領域だけ背景を復元してボールを再描画）
+		renderer.Render()
+
 		frame++
 	}
 }
@@ -4,7 +4,15 @@ import (
 	"github.com/ryomak/gameboys/common/gba/display"
 	"github.com/ryomak/gameboys/common/gba/graphics"
 	"github.com/ryomak/gameboys/common/gba/input"
+	"github.com/ryomak/gameboys/common/gba/palette"
+	"github.com/ryomak/gameboys/common/gba/particle"
+	"github.com/ryomak/gameboys/common/gba/power"
+	"github.com/ryomak/gameboys/common/gba/render3d"
+	"github.com/ryomak/gameboys/common/gba/sprite"
+	"github.com/ryomak/gameboys/common/gba/transition"
+	"github.com/ryomak/gameboys/common/gba/ui"
 	"github.com/ryomak/gameboys/common/math"
+	"github.com/ryomak/gameboys/common/util"
 )
 
 // GameState ゲームの状態
@@ -18,9 +26,272 @@ const (
 	StateResult                      // 結果表示
 )
 
+// パレットインデックス
+const (
+	PalBlack        = 0
+	PalWhite        = 1
+	PalRed          = 2
+	PalGreen        = 3
+	PalBlue         = 4
+	PalYellow       = 5
+	PalCyan         = 6
+	PalMagenta      = 7
+	PalGray         = 8
+	PalDarkGray     = 9
+	PalOrange       = 10
+	PalSkin         = 11
+	PalDarkSkin     = 12
+	PalFloor        = 13
+	PalLightFloor   = 14
+	PalWall         = 15
+	PalBackboard    = 16
+	PalRim          = 17
+	PalBall         = 18
+	PalDarkBall     = 19
+	PalLightBall    = 20
+	PalSuccessDark  = 21
+	PalSuccessLight = 22
+	PalFailDark     = 23
+	PalFailDarker   = 24
+	PalGold         = 25
+	PalUIBG         = 26
+	PalGaugeBG      = 27
+	PalBlueBG       = 28
+	PalOrangeBG     = 29
+)
+
+// gamePalette ゲームで使うパレット（インデックスは上の定数に対応）
+var gamePalette = [...]uint16{
+	PalBlack:        graphics.ColorBlack,
+	PalWhite:        graphics.ColorWhite,
+	PalRed:          graphics.ColorRed,
+	PalGreen:        graphics.ColorGreen,
+	PalBlue:         graphics.ColorBlue,
+	PalYellow:       graphics.ColorYellow,
+	PalCyan:         graphics.ColorCyan,
+	PalMagenta:      graphics.ColorMagenta,
+	PalGray:         graphics.ColorGray,
+	PalDarkGray:     graphics.ColorDarkGray,
+	PalOrange:       graphics.ColorOrange,
+	PalSkin:         graphics.RGB(220, 180, 140), // 肌色
+	PalDarkSkin:     graphics.RGB(180, 140, 100), // 暗い肌色
+	PalFloor:        graphics.RGB(139, 90, 43),   // 床色（木目）
+	PalLightFloor:   graphics.RGB(160, 110, 60),  // 明るい床色
+	PalWall:         graphics.RGB(30, 30, 50),    // 体育館の壁
+	PalBackboard:    graphics.RGB(200, 200, 200), // バックボード
+	PalRim:          graphics.RGB(255, 100, 0),   // リム色（オレンジ）
+	PalBall:         graphics.RGB(255, 120, 0),   // ボール色
+	PalDarkBall:     graphics.RGB(200, 80, 0),    // 暗いボール色
+	PalLightBall:    graphics.RGB(255, 160, 60),  // 明るいボール色
+	PalSuccessDark:  graphics.RGB(0, 150, 0),     // 成功色（濃い緑）
+	PalSuccessLight: graphics.RGB(0, 200, 0),     // 成功色（明るい緑）
+	PalFailDark:     graphics.RGB(150, 0, 0),     // 失敗色（濃い赤）
+	PalFailDarker:   graphics.RGB(100, 0, 0),     // 失敗色（暗い赤）
+	PalGold:         graphics.RGB(255, 200, 0),   // ゴールド
+	PalUIBG:         graphics.RGB(40, 40, 60),    // UI背景
+	PalGaugeBG:      graphics.RGB(60, 60, 60),    // ゲージ背景
+	PalBlueBG:       graphics.RGB(0, 0, 100),     // 青い背景
+	PalOrangeBG:     graphics.RGB(200, 100, 0),   // オレンジ系
+}
+
+// UIの配色
+var (
+	hudTheme = ui.Theme{
+		Background: PalUIBG,
+		Border:     PalWhite,
+		Text:       PalWhite,
+		Fill:       PalGreen,
+		Accent:     PalGray,
+		Empty:      PalDarkGray,
+	}
+	streakTheme = ui.Theme{
+		Background: PalGold,
+		Border:     PalYellow,
+		Empty:      PalGold,
+		Ramp:       []uint8{PalRed},
+	}
+	gaugeTheme = ui.Theme{
+		Border: PalWhite,
+		Shadow: PalBlack,
+		Empty:  PalGaugeBG,
+		Ramp:   []uint8{PalGreen, PalYellow, PalRed}, // 下から上に向かって緑→黄→赤
+	}
+	dialTheme = ui.Theme{
+		Background: PalBlack,
+		Border:     PalWhite,
+		Text:       PalYellow,
+	}
+	readyTheme = ui.Theme{
+		Background: PalBlueBG,
+		Border:     PalWhite,
+		Accent:     PalCyan,
+		Text:       PalYellow,
+	}
+	successTheme = ui.Theme{
+		Background: PalSuccessDark,
+		Border:     PalWhite,
+		Accent:     PalYellow,
+		Text:       PalYellow,
+	}
+	failTheme = ui.Theme{
+		Background: PalFailDark,
+		Border:     PalWhite,
+		Accent:     PalOrangeBG,
+		Text:       PalWhite,
+	}
+	bonusTheme = ui.Theme{
+		Background: PalGold,
+		Border:     PalRed,
+		Text:       PalRed,
+	}
+	promptTheme = ui.Theme{
+		Background: PalBlue,
+		Border:     PalWhite,
+		Text:       PalWhite,
+	}
+)
+
+// 角度計の中心
+const (
+	AngleDialX = graphics.ScreenWidth - 40
+	AngleDialY = 100
+)
+
+// HUD 画面上のUI
+// ウィジェットの配置はinitで宣言し、描画時は値だけを更新する
+type HUD struct {
+	score  ui.HUD // 常に表示
+	ready  ui.HUD // 待機状態
+	power  ui.HUD // パワーゲージ
+	angle  ui.HUD // 角度計のラベル
+	result ui.HUD // 結果表示
+
+	scoreBoard  ui.Panel
+	scoreIcons  ui.IconRow
+	streakBadge ui.Panel
+	streakMeter ui.Meter
+
+	readyPanel  ui.Panel
+	readyTitle  ui.Label
+	readyPrompt ui.Label
+
+	powerFrame ui.Panel
+	powerBar   ui.ProgressBar
+	powerTag   ui.Panel
+	powerLabel ui.Label
+
+	angleTag   ui.Panel
+	angleLabel ui.Label
+	angleFrame ui.Panel
+	angleValue ui.Label
+
+	resultPanel      ui.Panel
+	resultTitle      ui.Label
+	resultStreak     ui.Label
+	resultBonus      ui.Panel
+	resultBonusLabel ui.Label
+	resultPrompt     ui.Panel
+	resultPromptText ui.Label
+}
+
+// init ウィジェットを配置
+func (h *HUD) init() {
+	// スコアボード（成功数の丸と連続成功バッジ）
+	h.scoreBoard = ui.Panel{Box: ui.Box{Rect: util.NewRect(5, 5, 85, 20), Theme: &hudTheme}}
+	h.scoreIcons = ui.IconRow{
+		Box:     ui.Box{Rect: util.NewRect(7, 9, 78, 7), Theme: &hudTheme},
+		Count:   10,
+		Spacing: 8,
+	}
+	h.streakBadge = ui.Panel{Box: ui.Box{Rect: util.NewRect(95, 5, 40, 10), Theme: &streakTheme}}
+	h.streakMeter = ui.Meter{
+		Box:      ui.Box{Rect: util.NewRect(98, 8, 32, 4), Theme: &streakTheme},
+		Segments: 5,
+		Gap:      3,
+	}
+	h.score.Add(&h.scoreBoard)
+	h.score.Add(&h.scoreIcons)
+	h.score.Add(&h.streakBadge)
+	h.score.Add(&h.streakMeter)
+
+	// 待機メッセージ
+	readyRect := util.NewRect((graphics.ScreenWidth-120)/2, 70, 120, 30)
+	h.readyPanel = ui.Panel{Box: ui.Box{Rect: readyRect, Theme: &readyTheme}, Inset: 1}
+	h.readyTitle = ui.Label{
+		Box:   ui.Box{Rect: util.NewRect(readyRect.X, readyRect.Y+4, readyRect.Width, 12), Theme: &readyTheme},
+		Text:  "READY",
+		Align: ui.AlignCenter,
+		Scale: 2,
+	}
+	h.readyPrompt = ui.Label{
+		Box:   ui.Box{Rect: util.NewRect(readyRect.X, readyRect.Y+18, readyRect.Width, 8), Theme: &readyTheme},
+		Text:  DefaultReadyPrompt,
+		Align: ui.AlignCenter,
+	}
+	h.ready.Add(&h.readyPanel)
+	h.ready.Add(&h.readyTitle)
+	h.ready.Add(&h.readyPrompt)
+
+	// パワーゲージ
+	gaugeRect := util.NewRect(10, 50, 20, 100)
+	h.powerFrame = ui.Panel{Box: ui.Box{Rect: gaugeRect, Theme: &gaugeTheme}, Shadow: 2}
+	h.powerBar = ui.ProgressBar{
+		Box:      ui.Box{Rect: gaugeRect, Theme: &gaugeTheme},
+		Max:      MaxPower,
+		Vertical: true,
+		Padding:  2,
+		Ticks:    4,
+	}
+	h.powerTag = ui.Panel{Box: ui.Box{Rect: util.NewRect(gaugeRect.X-4, gaugeRect.Y-12, 28, 9), Theme: &hudTheme}}
+	h.powerLabel = ui.Label{Box: h.powerTag.Box, Text: "POWER", Align: ui.AlignCenter}
+	h.power.Add(&h.powerFrame)
+	h.power.Add(&h.powerBar)
+	h.power.Add(&h.powerTag)
+	h.power.Add(&h.powerLabel)
+
+	// 角度計のラベルと数値
+	h.angleTag = ui.Panel{Box: ui.Box{Rect: util.NewRect(AngleDialX-20, AngleDialY-50, 40, 9), Theme: &hudTheme}}
+	h.angleLabel = ui.Label{Box: h.angleTag.Box, Text: "ANGLE", Align: ui.AlignCenter}
+	h.angleFrame = ui.Panel{Box: ui.Box{Rect: util.NewRect(AngleDialX-10, AngleDialY+15, 20, 10), Theme: &dialTheme}}
+	h.angleValue = ui.Label{Box: h.angleFrame.Box, ShowValue: true, Align: ui.AlignCenter}
+	h.angle.Add(&h.angleTag)
+	h.angle.Add(&h.angleLabel)
+	h.angle.Add(&h.angleFrame)
+	h.angle.Add(&h.angleValue)
+
+	// 結果表示（テーマと文言は成否で切り替える）
+	resultRect := util.NewRect((graphics.ScreenWidth-140)/2, 60, 140, 50)
+	h.resultPanel = ui.Panel{Box: ui.Box{Rect: resultRect, Theme: &successTheme}, Inset: 2}
+	h.resultTitle = ui.Label{
+		Box:   ui.Box{Rect: util.NewRect(resultRect.X, resultRect.Y+8, resultRect.Width, 12), Theme: &successTheme},
+		Align: ui.AlignCenter,
+		Scale: 2,
+	}
+	h.resultStreak = ui.Label{
+		Box:       ui.Box{Rect: util.NewRect(resultRect.X, resultRect.Y+23, resultRect.Width, 8), Theme: &successTheme},
+		Text:      "STREAK",
+		ShowValue: true,
+		Align:     ui.AlignCenter,
+	}
+	h.resultBonus = ui.Panel{Box: ui.Box{Rect: util.NewRect(resultRect.X+30, resultRect.Y+35, 80, 10), Theme: &bonusTheme}}
+	h.resultBonusLabel = ui.Label{Box: h.resultBonus.Box, Text: "BONUS!", Align: ui.AlignCenter}
+	h.resultPrompt = ui.Panel{
+		Box: ui.Box{Rect: util.NewRect(resultRect.X+20, resultRect.Bottom()+10, 100, 9), Theme: &promptTheme},
+	}
+	h.resultPromptText = ui.Label{Box: h.resultPrompt.Box, Text: "PRESS A TO CONTINUE", Align: ui.AlignCenter}
+	h.result.Add(&h.resultPanel)
+	h.result.Add(&h.resultTitle)
+	h.result.Add(&h.resultStreak)
+	h.result.Add(&h.resultBonus)
+	h.result.Add(&h.resultBonusLabel)
+	h.result.Add(&h.resultPrompt)
+	h.result.Add(&h.resultPromptText)
+}
+
 // Game ゲーム全体の管理
 type Game struct {
 	state         GameState
+	nextState     GameState // 画面切り替えの後に移る状態
 	ball          Ball
 	goal          Goal
 	powerGauge    PowerGauge
@@ -28,6 +299,40 @@ type Game struct {
 	score         int32 // スコア
 	attempts      int32 // 試投数
 	consecutiveHits int32 // 連続成功数
+
+	pal         *palette.Manager // パレット管理
+	palFX       palette.Animator // パレットエフェクト
+	fadeIn      palette.Fade     // 起動時のフェードイン
+	streakFlash palette.Flash    // 連続成功バッジの点滅
+
+	hud     HUD              // 画面上のUI
+	actions *input.ActionMap // 操作とキー割り当て
+	rebind  bool             // 決定のキーを割り当て直している
+	armed   bool             // 割り当て直しで、すべてのキーが離されて次の入力を待っている
+	sampler *input.Sampler   // 押したときの走査線（パワーゲージ用、nilならフレーム単位）
+
+	showDebug bool                       // Lボタンを押している間、当たり判定と軌道を表示
+	camera    math.Camera                // 3D描画用のカメラ（ProjectSimpleと同じ見え方）
+	view      math.View                  // cameraのビュー変換
+	path      [DebugPathPoints]math.Vec3 // 予測軌道
+
+	particles *particle.System // 紙吹雪と土ぼこり（ワールド座標）
+	confetti  particle.Emitter // ゴール成功時の紙吹雪
+	dust      particle.Emitter // ボールが床に落ちたときの土ぼこり
+
+	iris       transition.Iris       // シュート後に画面を閉じるアイリス
+	transition transition.Transition // シュート中から結果表示への切り替え
+
+	sprites      *sprite.Manager       // スプライト（OAM）
+	tiles        *sprite.TileAllocator // OBJタイルの割り当て
+	queue        *render3d.Queue       // 奥行き順にスプライトを割り当てる
+	depthSprites [2]sprite.Handle      // queueが割り当てるスプライト（ボールとリムの手前側）
+	ballAffine   sprite.Affine         // ボールの回転・拡大縮小
+	ballTile     int                   // ボールの先頭タイル
+	ballItem     render3d.Item         // ボール（queueに追加する）
+	rimAffine    sprite.Affine         // リムの手前側の拡大縮小
+	rimTile      int                   // リムの手前側の先頭タイル
+	rimItem      render3d.Item         // リムの手前側（ボールが奥を通るときに前に出る）
 }
 
 // Ball バスケットボール
@@ -36,6 +341,7 @@ type Ball struct {
 	velocity  math.Vec3  // 速度ベクトル
 	isFlying  bool       // 飛んでいるか
 	radius    math.Fixed // ボールの半径（メートル）
+	spin      int32      // 回転角（0-255）
 }
 
 // Goal バスケットゴール
@@ -66,9 +372,144 @@ const (
 	AngleDefault   = 55   // デフォルト角度（度）
 )
 
+// 操作（キー割り当てはActionMapで変更できる）
+const (
+	ActionConfirm   input.Action = iota // 決定・シュート
+	ActionCancel                        // 戻る
+	ActionAngleUp                       // 角度を上げる
+	ActionAngleDown                     // 角度を下げる
+	ActionDebug                         // 押している間デバッグ表示
+	ActionExit                          // 終了
+)
+
+// newActions 操作と初期のキー割り当て
+func newActions() *input.ActionMap {
+	m := input.NewActionMap()
+	m.Define(ActionConfirm, "Confirm", input.KeyA)
+	m.Define(ActionCancel, "Cancel", input.KeyB)
+	m.Define(ActionAngleUp, "Angle Up", input.KeyUp)
+	m.Define(ActionAngleDown, "Angle Down", input.KeyDown)
+	m.Define(ActionDebug, "Debug", input.KeyL)
+	m.Define(ActionExit, "Exit", input.KeyStart|input.KeySelect)
+	m.Reserved = RebindKey
+	return m
+}
+
+// キー割り当ての変更と保存
+const (
+	RebindKey          = input.KeySelect // 待機中に長押しすると決定のキーを割り当て直す
+	ActionsSaveOffset  = 0               // 割り当てを保存するSRAMの位置
+	DefaultReadyPrompt = "PRESS A TO START"
+)
+
+// 角度調整のキーリピート（フレーム）
+const (
+	AngleRepeatDelay    = 12 // 押し続けてから1度ずつ動き始めるまで
+	AngleRepeatInterval = 2  // 動く間隔
+)
+
+// 入力のサンプリング（パワーゲージをフレームより細かいタイミングで止める）
+const (
+	InputSamplesPerFrame = 8 // 1フレームにキーを読む回数
+	InputTimer           = 3 // 使うタイマーのチャンネル
+)
+
+// パーティクル
+const (
+	MaxParticles  = 96 // 同時に表示できるパーティクルの数
+	ConfettiCount = 48 // ゴール成功時の紙吹雪の数
+	DustCount     = 16 // 床に落ちたときの土ぼこりの数
+)
+
+// ボールとリムのスプライト
+const (
+	BallSpinSpeed   = 6  // 飛んでいる間の1フレームの回転角（バックスピン）
+	RimSpriteRadius = 15 // リムの手前側のスプライト（32x16）に描く半円の半径
+)
+
+// ボールの表示倍率（射影のスケールに掛ける、16x16が1倍）
+var (
+	BallMinScale = math.NewFixedFloat(0.2)
+	BallMaxScale = math.NewFixedFloat(1.5)
+)
+
+// ballTiles ボールの16x16の絵（8bppのタイル4枚）
+var ballTiles [16 * 16 / 2]uint16
+
+// rimTiles リムの手前側の32x16の絵（8bppのタイル8枚、上端の中央がリムの中心）
+var rimTiles [32 * 16 / 2]uint16
+
+// setTilePixel 8bppのタイル列の1ピクセルを設定
+// 8x8のタイルごとに64バイト、タイルは横に並ぶ（1次元マッピング）
+func setTilePixel(tiles []uint16, width, px, py int, color uint8) {
+	tile := (py/8)*(width/8) + px/8
+	offset := tile*64 + (py%8)*8 + px%8
+	if offset%2 == 0 {
+		tiles[offset/2] |= uint16(color)
+	} else {
+		tiles[offset/2] |= uint16(color) << 8
+	}
+}
+
+// buildBallTiles ボールの絵を作る
+// パレット0番は透明になるため、線は黒ではなく濃い灰色で描く
+func buildBallTiles() {
+	for py := 0; py < 16; py++ {
+		for px := 0; px < 16; px++ {
+			// 中心からの距離（2倍した値）
+			dx := px*2 - 15
+			dy := py*2 - 15
+			d := dx*dx + dy*dy
+
+			color := uint8(0)
+			switch {
+			case d > 16*16:
+				color = 0 // 外側は透明
+			case dx >= -1 && dx <= 1, dy >= -1 && dy <= 1, dx-dy >= -1 && dx-dy <= 1:
+				color = PalDarkGray // ボールの線
+			case (dx+6)*(dx+6)+(dy+6)*(dy+6) <= 20:
+				color = PalLightBall // ハイライト（左上）
+			case d > 11*11 && dy > 0:
+				color = PalDarkBall // 影（下側）
+			default:
+				color = PalBall
+			}
+
+			setTilePixel(ballTiles[:], 16, px, py, color)
+		}
+	}
+}
+
+// buildRimTiles リムの手前側（下半分の円）の絵を作る
+// 太さはビットマップのリム（半径をずらした2本の円）に合わせる
+func buildRimTiles() {
+	outer := (RimSpriteRadius*2 + 1) * (RimSpriteRadius*2 + 1)
+	inner := (RimSpriteRadius*2 - 3) * (RimSpriteRadius*2 - 3)
+	for py := 0; py < 16; py++ {
+		for px := 0; px < 32; px++ {
+			// 上端の中央からの距離（2倍した値）
+			dx := px*2 + 1 - 32
+			dy := py*2 + 1
+			d := dx*dx + dy*dy
+			if d > inner && d <= outer {
+				setTilePixel(rimTiles[:], 32, px, py, PalRim)
+			}
+		}
+	}
+}
+
+// ResultTransitionFrames 結果表示への切り替えで画面を閉じる（開く）フレーム数
+const ResultTransitionFrames = 15
+
+// デバッグ表示
+const (
+	DebugPathPoints = 32 // 予測軌道の点の数
+	DebugPathStep   = 4  // 予測軌道の点の間隔（フレーム）
+)
+
 // NewGame ゲームを初期化
-func NewGame() *Game {
-	return &Game{
+func NewGame(pal *palette.Manager) *Game {
+	g := &Game{
 		state: StateReady,
 		ball: Ball{
 			pos:      math.NewVec3Fixed(0, math.NewFixed(PlayerHeight), 0),
@@ -89,11 +530,101 @@ func NewGame() *Game {
 		score:    0,
 		attempts: 0,
 		consecutiveHits: 0,
+		pal:      pal,
+	}
+
+	// UIの配置
+	g.hud.init()
+	g.actions = newActions()
+	// 保存した割り当てがあれば使う（他の操作とぶつかる割り当ては捨てる）
+	if g.actions.Load(ActionsSaveOffset) && g.actions.Conflicts(ActionConfirm, g.actions.Binding(ActionConfirm, 0)) {
+		g.actions.ResetDefaults()
+	}
+	g.updateReadyPrompt()
+
+	// 3D描画用のカメラ（ProjectSimpleのbaseDepth=300と同じ位置から見る）
+	g.camera = math.NewCamera()
+	g.camera.Position = math.NewVec3(0, 0, -300)
+	g.camera.Target = math.NewVec3(0, 0, 0)
+	g.camera.Near = math.NewFixed(300)
+	g.camera.Far = math.NewFixed(2000)
+	g.view = g.camera.View()
+
+	// パーティクル（速度は1フレームあたりのcm）
+	g.particles = particle.NewSystem(MaxParticles)
+	g.confetti = particle.Emitter{
+		Area:       math.NewVec3(10, 0, 10),
+		Velocity:   math.NewVec3(0, 4, 0),
+		Spread:     math.NewVec3(4, 2, 3),
+		Gravity:    math.NewVec3Fixed(0, math.NewFixedFloat(-0.15), 0),
+		Life:       40,
+		LifeSpread: 20,
+		Ramp:       palette.Range{Start: PalRed, Count: 6}, // 赤→緑→青→黄→シアン→マゼンタと色が変わる
+		Size:       4,
+	}
+	g.dust = particle.Emitter{
+		Area:       math.NewVec3(6, 0, 6),
+		Velocity:   math.NewVec3Fixed(0, math.NewFixedFloat(1.5), 0),
+		Spread:     math.NewVec3(3, 1, 3),
+		Gravity:    math.NewVec3Fixed(0, math.NewFixedFloat(-0.2), 0),
+		Life:       16,
+		LifeSpread: 8,
+		Ramp:       palette.Range{Start: PalGray, Count: 2}, // 灰色→暗い灰色
+		Size:       3,
+	}
+
+	// 結果表示への切り替え（画面中央に向かってアイリスで閉じる）
+	g.iris = transition.Iris{X: graphics.ScreenWidth / 2, Y: graphics.ScreenHeight / 2}
+	g.transition = transition.New(&g.iris, ResultTransitionFrames)
+
+	// ボール（回転・拡大縮小する）とリムの手前側のスプライト
+	// Mode 4ではOBJタイルの後半だけが使える
+	buildBallTiles()
+	buildRimTiles()
+	g.sprites = sprite.NewManager()
+	g.tiles = sprite.NewTileAllocator(sprite.BitmapTileStart, sprite.MaxTiles)
+	g.tiles.OnMove = func(name string, from, size, to int) {
+		g.sprites.MoveTiles(from, size, to)
+	}
+	g.ballTile, _ = g.tiles.Load("ball", ballTiles[:])
+	g.rimTile, _ = g.tiles.Load("rim", rimTiles[:])
+	g.ballAffine, _ = g.sprites.AllocAffine()
+	g.rimAffine, _ = g.sprites.AllocAffine()
+
+	// 奥行き順に並べて、手前のものに番号の小さいスプライトを割り当てる
+	g.queue = render3d.NewQueue()
+	for i := range g.depthSprites {
+		g.depthSprites[i], _ = g.sprites.Alloc()
+	}
+	g.ballItem.Sprite = render3d.SpriteFunc(g.placeBall)
+	g.rimItem.Sprite = render3d.SpriteFunc(g.placeRim)
+
+	// 起動時は黒からフェードイン
+	if colors, ok := pal.Lookup("freethrow"); ok {
+		g.fadeIn = palette.NewFadeIn(colors, graphics.ColorBlack, 30)
+		g.palFX.Add(&g.fadeIn)
+		g.palFX.Update(pal)
 	}
+
+	return g
 }
 
 // Update ゲームの状態を更新
 func (g *Game) Update(keys *input.KeyState) {
+	// パレットエフェクトを進める
+	g.palFX.Update(g.pal)
+
+	g.showDebug = g.actions.IsDown(keys, ActionDebug)
+	g.particles.Update()
+
+	// 画面の切り替え中は操作を受け付けない（閉じ切ったところで状態を切り替える）
+	if g.transition.Active() {
+		if g.transition.Update() {
+			g.state = g.nextState
+		}
+		return
+	}
+
 	switch g.state {
 	case StateReady:
 		g.updateReady(keys)
@@ -110,15 +641,52 @@ func (g *Game) Update(keys *input.KeyState) {
 
 // updateReady 待機状態の更新
 func (g *Game) updateReady(keys *input.KeyState) {
-	if keys.IsPressed(input.KeyA) {
+	if g.rebind {
+		g.updateRebind(keys)
+		return
+	}
+	if keys.IsLongPressed(RebindKey) {
+		g.rebind = true
+		g.armed = false
+		g.hud.readyPrompt.Text = "PRESS NEW SHOOT KEY"
+		return
+	}
+	if g.actions.IsPressed(keys, ActionConfirm) {
 		g.state = StatePowerGauge
 		g.powerGauge.power = 0
 		g.powerGauge.increasing = true
 	}
 }
 
+// updateRebind 次に押されたキー（同時押し）を決定に割り当ててSRAMに保存
+// 他の操作とぶつかるキー（B、L、Start、Select、上下）は受け付けず、押し直しを待つ
+func (g *Game) updateRebind(keys *input.KeyState) {
+	// 長押ししたキーを拾わないよう、すべてのキーが離されてから受け付ける
+	if !g.armed {
+		g.armed = keys.GetCurrent()&input.KeyAny == input.KeyAny
+		return
+	}
+	if !g.actions.CaptureBinding(keys, ActionConfirm, 0) {
+		return
+	}
+	g.actions.Save(ActionsSaveOffset)
+	g.rebind = false
+	g.updateReadyPrompt()
+}
+
+// updateReadyPrompt 決定のキーに合わせて待機メッセージを変える
+func (g *Game) updateReadyPrompt() {
+	if g.actions.Binding(ActionConfirm, 0) == input.Binding(input.KeyA) {
+		g.hud.readyPrompt.Text = DefaultReadyPrompt
+		return
+	}
+	g.hud.readyPrompt.Text = "PRESS KEY TO START"
+}
+
 // updatePowerGauge パワーゲージの更新
 func (g *Game) updatePowerGauge(keys *input.KeyState) {
+	prev := g.powerGauge.power
+
 	// パワーゲージを増減
 	if g.powerGauge.increasing {
 		g.powerGauge.power += g.powerGauge.speed
@@ -134,29 +702,43 @@ func (g *Game) updatePowerGauge(keys *input.KeyState) {
 		}
 	}
 
-	// Aボタンでパワー決定
-	if keys.IsPressed(input.KeyA) {
+	// 決定（初期はAボタン）でパワー決定
+	if g.actions.IsPressed(keys, ActionConfirm) {
+		g.powerGauge.power = g.pressedPower(prev)
 		g.state = StateAngleAdjust
 	}
 
-	// Bボタンでキャンセル
-	if keys.IsPressed(input.KeyB) {
+	// 戻る（初期はBボタン）でキャンセル
+	if g.actions.IsPressed(keys, ActionCancel) {
 		g.state = StateReady
 	}
 }
 
+// pressedPower 決定を押した走査線でのパワー（前のフレームの値とこのフレームの値の間を補間）
+func (g *Game) pressedPower(prev int32) int32 {
+	if g.sampler == nil {
+		return g.powerGauge.power
+	}
+	lines, ok := g.sampler.LinesSincePress(g.actions.Keys(ActionConfirm))
+	if !ok || lines >= input.LinesPerFrame {
+		return g.powerGauge.power
+	}
+	power := g.powerGauge.power
+	return power - (power-prev)*int32(lines)/input.LinesPerFrame
+}
+
 // updateAngleAdjust 角度調整の更新
 func (g *Game) updateAngleAdjust(keys *input.KeyState) {
-	// 上下キーで角度調整
+	// 上下キーで角度調整（押し続けるとリピート）
 	angleDeg := math.AngleToDeg(g.angle)
 
-	if keys.IsHeld(input.KeyUp) {
+	if g.actions.IsRepeated(keys, ActionAngleUp) {
 		angleDeg++
 		if angleDeg > MaxAngle {
 			angleDeg = MaxAngle
 		}
 	}
-	if keys.IsHeld(input.KeyDown) {
+	if g.actions.IsRepeated(keys, ActionAngleDown) {
 		angleDeg--
 		if angleDeg < MinAngle {
 			angleDeg = MinAngle
@@ -165,21 +747,28 @@ func (g *Game) updateAngleAdjust(keys *input.KeyState) {
 
 	g.angle = math.DegToAngle(angleDeg)
 
-	// Aボタンでシュート
-	if keys.IsPressed(input.KeyA) {
+	// 決定でシュート
+	if g.actions.IsPressed(keys, ActionConfirm) {
 		g.shoot()
 		g.state = StateShooting
 		g.attempts++
 	}
 
-	// Bボタンでキャンセル
-	if keys.IsPressed(input.KeyB) {
+	// 戻るでキャンセル
+	if g.actions.IsPressed(keys, ActionCancel) {
 		g.state = StatePowerGauge
 	}
 }
 
 // shoot シュートを実行
 func (g *Game) shoot() {
+	g.ball.velocity = g.shotVelocity()
+	g.ball.isFlying = true
+	g.ball.pos = math.NewVec3Fixed(0, math.NewFixed(PlayerHeight), 0)
+}
+
+// shotVelocity 現在のパワーと角度での初速度
+func (g *Game) shotVelocity() math.Vec3 {
 	// パワーから初速度を計算
 	// power: 0-100 -> velocity: 500-1500 cm/s
 	velocityMag := 500 + (g.powerGauge.power * 10)
@@ -188,9 +777,7 @@ func (g *Game) shoot() {
 	vz := math.NewFixed(velocityMag).Mul(math.Cos(g.angle))
 	vy := math.NewFixed(velocityMag).Mul(math.Sin(g.angle))
 
-	g.ball.velocity = math.NewVec3Fixed(0, vy, vz)
-	g.ball.isFlying = true
-	g.ball.pos = math.NewVec3Fixed(0, math.NewFixed(PlayerHeight), 0)
+	return math.NewVec3Fixed(0, vy, vz)
 }
 
 // updateShooting シュート中の更新
@@ -208,12 +795,16 @@ func (g *Game) updateShooting() {
 
 	// 位置を更新
 	g.ball.pos = g.ball.pos.Add(g.ball.velocity.Mul(dt))
+	g.ball.spin = (g.ball.spin + BallSpinSpeed) & 0xFF
 
 	// 地面に落ちたら終了
 	if g.ball.pos.Y < 0 {
+		g.dust.Position = math.NewVec3Fixed(g.ball.pos.X, 0, g.ball.pos.Z)
+		g.dust.Burst(g.particles, DustCount)
+
 		g.ball.isFlying = false
 		g.checkResult()
-		g.state = StateResult
+		g.changeState(StateResult)
 		return
 	}
 
@@ -222,10 +813,24 @@ func (g *Game) updateShooting() {
 		g.ball.isFlying = false
 		g.score++
 		g.consecutiveHits++
-		g.state = StateResult
+		g.changeState(StateResult)
+
+		// 連続成功バッジ（ゴールド）を点滅させる
+		g.streakFlash = palette.NewFlash(PalGold, graphics.ColorWhite, 4, 60)
+		g.palFX.Add(&g.streakFlash)
+
+		// 紙吹雪
+		g.confetti.Position = g.goal.pos
+		g.confetti.Burst(g.particles, ConfettiCount)
 	}
 }
 
+// changeState 画面切り替えの演出を挟んで状態を変える
+func (g *Game) changeState(next GameState) {
+	g.nextState = next
+	g.transition.Start()
+}
+
 // checkGoal ゴールに入ったか判定
 func (g *Game) checkGoal() bool {
 	// ボールの中心がゴールの高さ付近にあるか
@@ -260,7 +865,7 @@ func (g *Game) checkResult() {
 
 // updateResult 結果表示の更新
 func (g *Game) updateResult(keys *input.KeyState) {
-	if keys.IsPressed(input.KeyA) || keys.IsPressed(input.KeyB) {
+	if g.actions.IsPressed(keys, ActionConfirm) || g.actions.IsPressed(keys, ActionCancel) {
 		g.state = StateReady
 	}
 }
@@ -268,7 +873,7 @@ func (g *Game) updateResult(keys *input.KeyState) {
 // Draw ゲームを描画
 func (g *Game) Draw() {
 	// 背景をクリア（バックバッファに描画）
-	graphics.ClearMode4Screen(graphics.PalBlack)
+	graphics.ClearMode4Screen(PalBlack)
 
 	// コートを描画
 	g.drawCourt()
@@ -276,16 +881,66 @@ func (g *Game) Draw() {
 	// ゴールを描画（ボールより先に）
 	g.drawGoal()
 
-	// ボールを描画
-	g.drawBall()
+	// ボールとリムの手前側（スプライト）
+	g.drawSprites()
+
+	// 紙吹雪・土ぼこり
+	g.particles.DrawProjectedMode4(&g.view)
 
 	// プレイヤーの手を描画（一人称視点）
 	if g.state != StateShooting {
 		g.drawPlayerHands()
 	}
 
+	// デバッグ表示
+	if g.showDebug {
+		g.drawDebug()
+	}
+
 	// UIを描画
 	g.drawUI()
+
+	// 画面切り替えの演出（ビットマップに描く効果の場合）
+	g.transition.Draw()
+}
+
+// drawDebug 床のグリッド、ゴールの当たり判定、予測軌道を3Dの線で描画
+func (g *Game) drawDebug() {
+	view := &g.view
+
+	// 床（ゴールまでを含む範囲）
+	floorCenter := math.NewVec3Fixed(0, 0, g.goal.pos.Z.Div(math.NewFixed(2)))
+	render3d.DrawGrid3DMode4(view, floorCenter, math.NewFixed(800), 8, render3d.PlaneXZ, PalDarkGray)
+
+	// リムと当たり判定（checkGoalの判定範囲）
+	render3d.DrawCircle3DMode4(view, g.goal.pos, g.goal.radius, render3d.PlaneXZ, PalRim)
+	r := g.goal.radius
+	render3d.DrawBox3DMode4(view,
+		math.NewVec3Fixed(g.goal.pos.X.Sub(r), g.goal.pos.Y.Sub(r), g.goal.pos.Z),
+		math.NewVec3Fixed(g.goal.pos.X.Add(r), g.goal.pos.Y.Add(r), g.goal.pos.Z.Add(math.NewFixed(50))),
+		PalGreen)
+
+	// ボール
+	render3d.DrawSphere3DMode4(view, g.ball.pos, g.ball.radius, PalCyan)
+
+	// 現在のパワーと角度での予測軌道（updateShootingと同じ計算）
+	dt := math.NewFixedFloat(float64(DeltaTime) / 1000.0)
+	gravity := math.NewFixed(Gravity)
+	pos := math.NewVec3Fixed(0, math.NewFixed(PlayerHeight), 0)
+	velocity := g.shotVelocity()
+	count := 0
+	for frame := 0; count < DebugPathPoints; frame++ {
+		if frame%DebugPathStep == 0 {
+			g.path[count] = pos
+			count++
+		}
+		if pos.Y < 0 {
+			break
+		}
+		velocity.Y = velocity.Y.Sub(gravity.Mul(dt))
+		pos = pos.Add(velocity.Mul(dt))
+	}
+	render3d.DrawPolyline3DMode4(view, g.path[:count], PalYellow)
 }
 
 // drawPlayerHands プレイヤーの手を描画
@@ -295,14 +950,14 @@ func (g *Game) drawPlayerHands() {
 	leftHandY := graphics.ScreenHeight - 30
 
 	// 左腕
-	graphics.FillRectMode4(leftHandX, leftHandY, 25, 35, graphics.PalDarkSkin)
-	graphics.FillRectMode4(leftHandX+2, leftHandY+2, 21, 31, graphics.PalSkin)
+	graphics.FillRectMode4(leftHandX, leftHandY, 25, 35, PalDarkSkin)
+	graphics.FillRectMode4(leftHandX+2, leftHandY+2, 21, 31, PalSkin)
 
 	// 左手の指
 	for i := 0; i < 4; i++ {
 		fingerX := leftHandX + 5 + i*5
 		fingerY := leftHandY + 30
-		graphics.FillRectMode4(fingerX, fingerY, 3, 8, graphics.PalSkin)
+		graphics.FillRectMode4(fingerX, fingerY, 3, 8, PalSkin)
 	}
 
 	// 右手（画面右下）- シュート準備の位置
@@ -310,21 +965,21 @@ func (g *Game) drawPlayerHands() {
 	rightHandY := graphics.ScreenHeight - 40
 
 	// 右腕
-	graphics.FillRectMode4(rightHandX, rightHandY, 30, 40, graphics.PalDarkSkin)
-	graphics.FillRectMode4(rightHandX+2, rightHandY+2, 26, 36, graphics.PalSkin)
+	graphics.FillRectMode4(rightHandX, rightHandY, 30, 40, PalDarkSkin)
+	graphics.FillRectMode4(rightHandX+2, rightHandY+2, 26, 36, PalSkin)
 
 	// 右手の指（開いた状態）
 	for i := 0; i < 5; i++ {
 		fingerX := rightHandX + 5 + i*5
 		fingerY := rightHandY + 35
-		graphics.FillRectMode4(fingerX, fingerY, 3, 10, graphics.PalSkin)
+		graphics.FillRectMode4(fingerX, fingerY, 3, 10, PalSkin)
 	}
 }
 
 // drawCourt コートを描画
 func (g *Game) drawCourt() {
 	// 背景（体育館の壁）- 上部は暗め
-	graphics.FillRectMode4(0, 0, graphics.ScreenWidth, 60, graphics.PalWall)
+	graphics.FillRectMode4(0, 0, graphics.ScreenWidth, 60, PalWall)
 
 	// 床を段階的に描画して遠近感を出す
 	for y := 90; y < graphics.ScreenHeight; y++ {
@@ -334,92 +989,61 @@ func (g *Game) drawCourt() {
 		startX := (graphics.ScreenWidth - width) / 2
 
 		// 交互に色を変えて木目風に
-		colorIndex := uint8(graphics.PalFloor)
+		colorIndex := uint8(PalFloor)
 		if (y/4)%2 == 0 {
-			colorIndex = uint8(graphics.PalLightFloor)
+			colorIndex = uint8(PalLightFloor)
 		}
 
 		graphics.DrawLineMode4(startX, y, startX+width, y, colorIndex)
 	}
 
 	// フリースローライン（白線）
-	graphics.DrawLineMode4(80, 145, 160, 145, graphics.PalWhite)
-	graphics.DrawLineMode4(80, 146, 160, 146, graphics.PalWhite)
+	graphics.DrawLineMode4(80, 145, 160, 145, PalWhite)
+	graphics.DrawLineMode4(80, 146, 160, 146, PalWhite)
 
 	// ペイントエリアの線
-	graphics.DrawLineMode4(60, 140, 60, 155, graphics.PalWhite)
-	graphics.DrawLineMode4(180, 140, 180, 155, graphics.PalWhite)
+	graphics.DrawLineMode4(60, 140, 60, 155, PalWhite)
+	graphics.DrawLineMode4(180, 140, 180, 155, PalWhite)
 }
 
-// drawBall ボールを描画（3D→2D変換）
-func (g *Game) drawBall() {
-	// 簡易的な射影変換
-	baseDepth := math.NewFixed(300)
-	result := math.ProjectSimple(g.ball.pos, graphics.ScreenWidth, graphics.ScreenHeight, baseDepth)
+// drawSprites ボールとリムの手前側を奥行き順にスプライトへ割り当てる
+// ボールがリムの手前の縁より奥にあるときは、リムの手前側がボールの前に表示される
+func (g *Game) drawSprites() {
+	g.queue.Begin(&g.camera)
 
-	if !result.Visible {
-		return
-	}
+	g.ballItem.Pos = g.ball.pos
+	g.queue.Add(&g.ballItem)
 
-	// スケールに応じたボールサイズ
-	size := result.Scale.Mul(math.NewFixed(16)).ToInt()
-	if size < 2 {
-		size = 2
-	}
-	if size > 32 {
-		size = 32
-	}
+	g.rimItem.Pos = g.goal.pos
+	g.rimItem.Bias = g.goal.radius.Neg()
+	g.queue.Add(&g.rimItem)
 
-	radius := int(size / 2)
+	g.queue.Flush(g.sprites, g.depthSprites[:])
+}
 
-	// バスケットボールを描画（グラデーションで立体感）
-	// 影の部分（下側）
-	for r := radius; r >= radius*2/3; r-- {
-		graphics.DrawCircleMode4(int(result.ScreenX), int(result.ScreenY+1), r, graphics.PalDarkBall)
-	}
+// placeBall ボールのスプライトを配置（距離に応じた大きさで回転する）
+func (g *Game) placeBall(m *sprite.Manager, h sprite.Handle, p math.ProjectionResult) {
+	s := m.Sprite(h)
+	s.SetSize(sprite.Size16x16)
+	s.SetColor256(true)
+	s.SetTile(g.ballTile)
+	p.Scale = p.Scale.Clamp(BallMinScale, BallMaxScale)
+	m.PlaceProjected(h, g.ballAffine, p, g.ball.spin, math.FixedOne)
+}
 
-	// メインの色
-	graphics.FillCircleMode4(int(result.ScreenX), int(result.ScreenY), radius, graphics.PalBall)
+// placeRim リムの手前側のスプライトを配置（ビットマップのリムに重ねる）
+func (g *Game) placeRim(m *sprite.Manager, h sprite.Handle, p math.ProjectionResult) {
+	s := m.Sprite(h)
+	s.SetSize(sprite.Size32x16)
+	s.SetColor256(true)
+	s.SetTile(g.rimTile)
 
-	// ハイライト（上側）
-	highlightRadius := radius / 3
-	if highlightRadius > 0 {
-		graphics.FillCircleMode4(
-			int(result.ScreenX-int32(radius/4)),
-			int(result.ScreenY-int32(radius/4)),
-			highlightRadius,
-			graphics.PalLightBall,
-		)
-	}
+	// drawGoalのリムの半径（goalSize/2）に合わせる
+	scale := p.Scale.Mul(math.NewFixed(35)).Div(math.NewFixed(2 * RimSpriteRadius))
+	m.SetRotScale(h, g.rimAffine, 0, scale)
 
-	// バスケットボールの線（黒いライン）
-	if radius >= 4 {
-		// 縦線
-		graphics.DrawLineMode4(
-			int(result.ScreenX),
-			int(result.ScreenY-int32(radius)),
-			int(result.ScreenX),
-			int(result.ScreenY+int32(radius)),
-			graphics.PalBlack,
-		)
-		// 横線
-		graphics.DrawLineMode4(
-			int(result.ScreenX-int32(radius)),
-			int(result.ScreenY),
-			int(result.ScreenX+int32(radius)),
-			int(result.ScreenY),
-			graphics.PalBlack,
-		)
-		// 斜め線
-		offset := int32(radius * 7 / 10)
-		graphics.DrawLineMode4(
-			int(result.ScreenX-offset),
-			int(result.ScreenY-offset),
-			int(result.ScreenX+offset),
-			int(result.ScreenY+offset),
-			graphics.PalBlack,
-		)
-	}
+	// 絵の上端の中央がリムの中心なので、スプライトの中心は半分の高さだけ下
+	s.SetCenter(int(p.ScreenX), int(p.ScreenY+math.NewFixed(8).Mul(scale).ToInt()))
 }
 
 // drawGoal ゴールを描画
@@ -436,7 +1060,7 @@ func (g *Game) drawGoal() {
 		boardHeight := backboardResult.Scale.Mul(math.NewFixed(45)).ToInt()
 
 		// バックボード（半透明の白）
-		backboardColor := uint8(graphics.PalBackboard)
+		backboardColor := uint8(PalBackboard)
 		graphics.FillRectMode4(
 			int(backboardResult.ScreenX-boardWidth/2),
 			int(backboardResult.ScreenY-boardHeight/2),
@@ -451,7 +1075,7 @@ func (g *Game) drawGoal() {
 			int(backboardResult.ScreenY-boardHeight/2),
 			int(boardWidth),
 			int(boardHeight),
-			graphics.PalRed,
+			PalRed,
 		)
 
 		// 四角いターゲット（内側の四角）
@@ -461,7 +1085,7 @@ func (g *Game) drawGoal() {
 			int(backboardResult.ScreenY-targetSize/4),
 			int(targetSize),
 			int(targetSize/2),
-			graphics.PalRed,
+			PalRed,
 		)
 	}
 
@@ -479,7 +1103,7 @@ func (g *Game) drawGoal() {
 	}
 
 	// リム（楕円で立体感）- オレンジ色
-	rimColor := uint8(graphics.PalRim)
+	rimColor := uint8(PalRim)
 
 	// リムの外側
 	graphics.DrawCircleMode4(int(result.ScreenX), int(result.ScreenY), int(goalSize/2+1), rimColor)
@@ -499,7 +1123,7 @@ func (g *Game) drawGoal() {
 				int(result.ScreenY),
 				int(netResult.ScreenX),
 				int(netResult.ScreenY),
-				graphics.PalWhite,
+				PalWhite,
 			)
 		}
 	}
@@ -511,7 +1135,7 @@ func (g *Game) drawGoal() {
 			int(result.ScreenX),
 			int(result.ScreenY+offsetY),
 			int(goalSize/2-(goalSize/8)*i),
-			graphics.PalWhite,
+			PalWhite,
 		)
 	}
 }
@@ -536,106 +1160,28 @@ func (g *Game) drawUI() {
 
 // drawScore スコアを描画
 func (g *Game) drawScore() {
-	// スコアボード背景
-	graphics.FillRectMode4(5, 5, 85, 20, graphics.PalUIBG)
-	graphics.DrawRectMode4(5, 5, 85, 20, graphics.PalWhite)
-
-	// 成功数（緑の丸）
-	for i := int32(0); i < g.score && i < 10; i++ {
-		graphics.FillCircleMode4(int(10+i*8), 12, 3, graphics.PalGreen)
-	}
-
-	// 試投数の枠（グレー）
-	for i := int32(0); i < 10; i++ {
-		color := uint8(graphics.PalDarkGray)
-		if i < g.attempts {
-			color = uint8(graphics.PalGray)
-		}
-		graphics.DrawCircleMode4(int(10+i*8), 12, 3, color)
-	}
+	h := &g.hud
+	// 成功数（緑の丸）と試投数の枠（グレー）
+	h.scoreIcons.Filled = g.score
+	h.scoreIcons.Marked = g.attempts
 
 	// 連続成功数の表示
-	if g.consecutiveHits > 0 {
-		graphics.FillRectMode4(95, 5, 40, 10, graphics.PalGold)
-		graphics.DrawRectMode4(95, 5, 40, 10, graphics.PalYellow)
-		// "STREAK" 表示（簡易版）
-		for i := int32(0); i < g.consecutiveHits && i < 5; i++ {
-			graphics.FillRectMode4(int(98+i*7), 8, 4, 4, graphics.PalRed)
-		}
-	}
+	h.streakBadge.Hidden = g.consecutiveHits == 0
+	h.streakMeter.Hidden = g.consecutiveHits == 0
+	h.streakMeter.Value = g.consecutiveHits
+
+	h.score.Draw()
 }
 
 // drawReadyUI 待機状態のUI
 func (g *Game) drawReadyUI() {
-	// "READY - Press A to Start" メッセージ
-	msgWidth := 120
-	msgHeight := 30
-	msgX := (graphics.ScreenWidth - msgWidth) / 2
-	msgY := 70
-
-	// 背景（半透明風）
-	graphics.FillRectMode4(msgX, msgY, msgWidth, msgHeight, graphics.PalBlueBG)
-	graphics.DrawRectMode4(msgX, msgY, msgWidth, msgHeight, graphics.PalWhite)
-	graphics.DrawRectMode4(msgX+1, msgY+1, msgWidth-2, msgHeight-2, graphics.PalCyan)
-
-	// "READY" 文字（ブロック表示）
-	graphics.FillRectMode4(msgX+15, msgY+8, 30, 6, graphics.PalYellow)
-	graphics.FillRectMode4(msgX+50, msgY+8, 25, 6, graphics.PalYellow)
-	graphics.FillRectMode4(msgX+80, msgY+8, 25, 6, graphics.PalYellow)
-
-	// "Press A" 点滅風（フレームで切り替え）
-	graphics.FillRectMode4(msgX+35, msgY+18, 50, 8, graphics.PalGreen)
+	g.hud.ready.Draw()
 }
 
 // drawPowerGauge パワーゲージを描画
 func (g *Game) drawPowerGauge() {
-	// ゲージの枠（立体感）
-	gaugeX := 10
-	gaugeY := 50
-	gaugeWidth := 20
-	gaugeHeight := 100
-
-	// 外枠（影）
-	graphics.FillRectMode4(gaugeX+2, gaugeY+2, gaugeWidth, gaugeHeight, graphics.PalBlack)
-	// メイン枠
-	graphics.FillRectMode4(gaugeX, gaugeY, gaugeWidth, gaugeHeight, graphics.PalGaugeBG)
-	graphics.DrawRectMode4(gaugeX, gaugeY, gaugeWidth, gaugeHeight, graphics.PalWhite)
-
-	// 目盛り
-	for i := 0; i <= 4; i++ {
-		markY := gaugeY + (gaugeHeight * i / 4)
-		graphics.DrawLineMode4(gaugeX, markY, gaugeX+4, markY, graphics.PalWhite)
-		graphics.DrawLineMode4(gaugeX+gaugeWidth-4, markY, gaugeX+gaugeWidth, markY, graphics.PalWhite)
-	}
-
-	// ゲージの中身（グラデーション）
-	fillHeight := (gaugeHeight * int(g.powerGauge.power)) / MaxPower
-	if fillHeight > 0 {
-		for i := 0; i < fillHeight; i++ {
-			// 下から上に向かって色が変わる（緑→黄→赤）
-			ratio := float64(i) / float64(gaugeHeight)
-			var color uint8
-			if ratio < 0.33 {
-				color = graphics.PalGreen // 緑
-			} else if ratio < 0.66 {
-				color = graphics.PalYellow // 黄
-			} else {
-				color = graphics.PalRed // 赤
-			}
-
-			graphics.DrawLineMode4(
-				gaugeX+2,
-				gaugeY+gaugeHeight-i,
-				gaugeX+gaugeWidth-2,
-				gaugeY+gaugeHeight-i,
-				color,
-			)
-		}
-	}
-
-	// "POWER" ラベル
-	graphics.FillRectMode4(gaugeX-2, gaugeY-12, 24, 8, graphics.PalUIBG)
-	graphics.DrawRectMode4(gaugeX-2, gaugeY-12, 24, 8, graphics.PalWhite)
+	g.hud.powerBar.Value = g.powerGauge.power
+	g.hud.power.Draw()
 }
 
 // drawAngleIndicator 角度インジケーターを描画
@@ -644,131 +1190,93 @@ func (g *Game) drawAngleIndicator() {
 	g.drawPowerGauge()
 
 	// 角度計の背景
-	centerX := graphics.ScreenWidth - 40
-	centerY := 100
+	centerX := AngleDialX
+	centerY := AngleDialY
 	radius := int32(35)
 
 	// 背景円
-	graphics.FillCircleMode4(centerX, centerY, int(radius+5), graphics.PalUIBG)
-	graphics.DrawCircleMode4(centerX, centerY, int(radius+5), graphics.PalWhite)
+	graphics.FillCircleMode4(centerX, centerY, int(radius+5), PalUIBG)
+	graphics.DrawCircleMode4(centerX, centerY, int(radius+5), PalWhite)
 
 	// 角度の範囲を示す弧（30-80度）
-	for a := int32(MinAngle); a <= MaxAngle; a += 2 {
-		angle := math.DegToAngle(a)
-		x := centerX + int(math.Cos(angle).Mul(math.NewFixed(radius)).ToInt())
-		y := centerY - int(math.Sin(angle).Mul(math.NewFixed(radius)).ToInt())
-		graphics.DrawPixelMode4(x, y, graphics.PalGreen)
-	}
+	graphics.DrawArcMode4(centerX, centerY, int(radius), math.DegToAngle(MinAngle), math.DegToAngle(MaxAngle), PalGreen)
 
 	// 現在の角度を示す線
-	angleDeg := math.AngleToDeg(g.angle)
 	endX := centerX + int(math.Cos(g.angle).Mul(math.NewFixed(radius-5)).ToInt())
 	endY := centerY - int(math.Sin(g.angle).Mul(math.NewFixed(radius-5)).ToInt())
 
 	// 角度の針（太め）
-	graphics.DrawLineMode4(centerX, centerY, endX, endY, graphics.PalYellow)
-	graphics.DrawLineMode4(centerX+1, centerY, endX+1, endY, graphics.PalYellow)
-	graphics.DrawLineMode4(centerX, centerY+1, endX, endY+1, graphics.PalYellow)
+	graphics.DrawThickLineMode4(centerX, centerY, endX, endY, 3, PalYellow)
 
 	// 中心点
-	graphics.FillCircleMode4(centerX, centerY, 3, graphics.PalRed)
+	graphics.FillCircleMode4(centerX, centerY, 3, PalRed)
 
 	// 最適角度（45度）を表示
 	optimalAngle := math.DegToAngle(45)
 	optX := centerX + int(math.Cos(optimalAngle).Mul(math.NewFixed(radius)).ToInt())
 	optY := centerY - int(math.Sin(optimalAngle).Mul(math.NewFixed(radius)).ToInt())
-	graphics.FillCircleMode4(optX, optY, 2, graphics.PalGreen)
-
-	// "ANGLE" ラベル
-	graphics.FillRectMode4(centerX-20, centerY-50, 40, 8, graphics.PalUIBG)
-	graphics.DrawRectMode4(centerX-20, centerY-50, 40, 8, graphics.PalWhite)
+	graphics.FillCircleMode4(optX, optY, 2, PalGreen)
 
-	// 角度の数値表示（簡易版）
-	digitX := centerX - 10
-	digitY := centerY + 15
-	graphics.FillRectMode4(digitX, digitY, 20, 10, graphics.PalBlack)
-	graphics.DrawRectMode4(digitX, digitY, 20, 10, graphics.PalWhite)
-
-	// 角度の10の位と1の位を簡易表示
-	tens := angleDeg / 10
-	ones := angleDeg % 10
-	for i := int32(0); i < tens && i < 9; i++ {
-		graphics.FillRectMode4(digitX+2, digitY+2+int(i), 6, 1, graphics.PalYellow)
-	}
-	for i := int32(0); i < ones && i < 9; i++ {
-		graphics.FillRectMode4(digitX+12, digitY+2+int(i), 6, 1, graphics.PalYellow)
-	}
+	// "ANGLE" ラベルと角度の数値
+	g.hud.angleValue.Value = math.AngleToDeg(g.angle)
+	g.hud.angle.Draw()
 }
 
 // drawResultUI 結果表示
 func (g *Game) drawResultUI() {
-	msgWidth := 140
-	msgHeight := 50
-	msgX := (graphics.ScreenWidth - msgWidth) / 2
-	msgY := 60
+	h := &g.hud
 
 	// 最後のシュートが成功したか判定（直前の状態から）
 	lastSuccess := g.score > 0 && g.consecutiveHits > 0
 
 	if lastSuccess {
-		// 成功！
-		// 背景（緑）
-		graphics.FillRectMode4(msgX, msgY, msgWidth, msgHeight, graphics.PalSuccessDark)
-		graphics.DrawRectMode4(msgX, msgY, msgWidth, msgHeight, graphics.PalWhite)
-		graphics.DrawRectMode4(msgX+2, msgY+2, msgWidth-4, msgHeight-4, graphics.PalYellow)
-
-		// "GOOD!" 文字風
-		graphics.FillRectMode4(msgX+20, msgY+10, 100, 15, graphics.PalYellow)
-		graphics.FillRectMode4(msgX+25, msgY+12, 90, 11, graphics.PalSuccessLight)
-
-		// 星（装飾）
-		for i := 0; i < 5; i++ {
-			starX := msgX + 30 + i*20
-			starY := msgY + 30
-			graphics.FillRectMode4(starX-2, starY, 5, 1, graphics.PalYellow)
-			graphics.FillRectMode4(starX, starY-2, 1, 5, graphics.PalYellow)
-		}
-
-		// 連続成功ボーナス表示
-		if g.consecutiveHits >= 3 {
-			graphics.FillRectMode4(msgX+30, msgY+35, 80, 10, graphics.PalGold)
-			graphics.DrawRectMode4(msgX+30, msgY+35, 80, 10, graphics.PalRed)
-		}
+		h.resultPanel.Theme = &successTheme
+		h.resultTitle.Theme = &successTheme
+		h.resultTitle.Text = "GOOD!"
 	} else {
-		// 失敗...
-		// 背景（赤）
-		graphics.FillRectMode4(msgX, msgY, msgWidth, msgHeight, graphics.PalFailDark)
-		graphics.DrawRectMode4(msgX, msgY, msgWidth, msgHeight, graphics.PalWhite)
-		graphics.DrawRectMode4(msgX+2, msgY+2, msgWidth-4, msgHeight-4, graphics.PalOrangeBG)
-
-		// "MISS" 文字風
-		graphics.FillRectMode4(msgX+20, msgY+10, 100, 15, graphics.PalRed)
-		graphics.FillRectMode4(msgX+25, msgY+12, 90, 11, graphics.PalFailDarker)
-
-		// X マーク
-		for i := 0; i < 20; i++ {
-			graphics.DrawPixelMode4(msgX+40+i, msgY+25+i, graphics.PalRed)
-			graphics.DrawPixelMode4(msgX+60-i, msgY+25+i, graphics.PalRed)
-		}
+		h.resultPanel.Theme = &failTheme
+		h.resultTitle.Theme = &failTheme
+		h.resultTitle.Text = "MISS"
 	}
 
-	// "Press A to Continue"
-	graphics.FillRectMode4(msgX+20, msgY+msgHeight+10, 100, 8, graphics.PalBlue)
-	graphics.DrawRectMode4(msgX+20, msgY+msgHeight+10, 100, 8, graphics.PalWhite)
+	// 連続成功数と連続成功ボーナス
+	h.resultStreak.Hidden = !lastSuccess
+	h.resultStreak.Value = g.consecutiveHits
+	h.resultBonus.Hidden = !lastSuccess || g.consecutiveHits < 3
+	h.resultBonusLabel.Hidden = h.resultBonus.Hidden
+
+	h.result.Draw()
 }
 
 func main() {
 	// ディスプレイ初期化（Mode 4: ダブルバッファリング対応）
-	display.SetMode(display.Mode4 | display.EnableBG2)
+	display.SetMode(display.Mode4 | display.EnableBG2 | display.EnableOBJ | display.OBJVRAMMapping)
+
+	// パレット初期化（ゲーム用の範囲を確保してシャドウコピーに読み込む）
+	pal := palette.NewManager(palette.RegPaletteBG)
+	gameColors, _ := pal.ReserveNamed("freethrow", 0, len(gamePalette))
+	pal.LoadRange(gameColors, gamePalette[:])
 
-	// パレット初期化
-	graphics.InitMode4Palette()
+	// スプライト用パレット（背景と同じ色を同じ番号に置く）
+	objPal := palette.NewManager(palette.RegPaletteOBJ)
+	objColors, _ := objPal.ReserveNamed("freethrow", 0, len(gamePalette))
+	objPal.LoadRange(objColors, gamePalette[:])
 
 	// 入力初期化
 	keys := input.NewKeyState()
+	keys.SetRepeat(input.KeyUp|input.KeyDown, AngleRepeatDelay, AngleRepeatInterval)
 
 	// ゲーム初期化
-	game := NewGame()
+	game := NewGame(pal)
+
+	// キーを1フレームに何度も読み、押した走査線を記録する
+	sampler := input.NewSampler()
+	sampler.StartTimer(InputTimer, InputSamplesPerFrame)
+	game.sampler = sampler
+
+	// 初期パレット（フェードイン開始時の色）を転送
+	pal.Commit()
+	objPal.Commit()
 
 	// 最初はバッファ1を表示、バッファ0に描画
 	display.SetFrameBuffer(1)
@@ -781,6 +1289,14 @@ func main() {
 		// VBlank待機（画面の書き換えタイミング）
 		display.WaitForVBlank()
 
+		// パレットの変更を反映（スプライトにも背景と同じエフェクトをかける）
+		pal.Commit()
+		objPal.LoadRange(objColors, pal.Colors(gameColors))
+		objPal.Commit()
+
+		// スプライトの変更を反映
+		game.sprites.Commit()
+
 		// 描画完了したバッファを表示に切り替え
 		// 描画先が0なら表示を0に、描画先が1なら表示を1に
 		displayBuffer := graphics.GetCurrentDrawBuffer()
@@ -791,9 +1307,13 @@ func main() {
 
 		// 入力更新
 		keys.Update()
+		sampler.Update()
+
+		// L+R+Selectでスリープ（同じキーで復帰）
+		power.CheckSleep(keys)
 
-		// ゲーム終了チェック（Start + Select）
-		if keys.IsHeld(input.KeyStart) && keys.IsHeld(input.KeySelect) {
+		// ゲーム終了チェック（初期はStart + Select）
+		if game.actions.IsDown(keys, ActionExit) {
 			break
 		}
 